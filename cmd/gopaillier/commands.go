@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// readInput returns the content of the file provided, or of the stdin if the
+// path is empty or "-".
+func readInput(path string, stdin io.Reader) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// writeOutput encodes the provided value as JSON and writes it into the file
+// provided with the provided permissions, or into the stdout if the path is
+// empty or "-".
+func writeOutput(path string, stdout io.Writer, value interface{}, perm os.FileMode) error {
+	var data, err = json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if path == "" || path == "-" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// readJSON decodes the JSON content of the file provided, or of the stdin, into
+// the value provided.
+func readJSON(path string, stdin io.Reader, value interface{}) error {
+	var data, err = readInput(path, stdin)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// loadPublicKey returns the paillier.PublicKey stored into the file provided.
+func loadPublicKey(path string) (*paillier.PublicKey, error) {
+	if path == "" {
+		return nil, errors.New("public key file is required (-pubkey)")
+	}
+
+	var pubKey = new(paillier.PublicKey)
+	if err := readJSON(path, nil, pubKey); err != nil {
+		return nil, fmt.Errorf("error reading public key: %w", err)
+	} else if pubKey.N == nil || pubKey.Nsq == nil || pubKey.G == nil {
+		return nil, errors.New("invalid public key, some parameters are missing")
+	}
+	return pubKey, nil
+}
+
+// readPassphrase returns the content of the passphrase file provided, without
+// the trailing line break.
+func readPassphrase(path string) ([]byte, error) {
+	var data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading passphrase: %w", err)
+	}
+
+	data = bytes.TrimRight(data, "\r\n")
+	if len(data) == 0 {
+		return nil, errors.New("the passphrase must not be empty")
+	}
+	return data, nil
+}
+
+// loadPrivateKey returns the paillier.PrivateKey stored into the file
+// provided. If a passphrase file is provided, the key is imported with
+// paillier.ImportPrivateKey, otherwise it is read in clear with
+// paillier.ImportInsecurePlaintext.
+func loadPrivateKey(path, passphrasePath string) (*paillier.PrivateKey, error) {
+	if path == "" {
+		return nil, errors.New("private key file is required (-key)")
+	}
+
+	var data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading private key: %w", err)
+	}
+
+	var key *paillier.PrivateKey
+	if passphrasePath == "" {
+		if key, err = paillier.ImportInsecurePlaintext(data); err != nil {
+			return nil, fmt.Errorf("error reading private key: %w", err)
+		}
+		return key, nil
+	}
+
+	var passphrase []byte
+	if passphrase, err = readPassphrase(passphrasePath); err != nil {
+		return nil, err
+	} else if key, err = paillier.ImportPrivateKey(data, passphrase); err != nil {
+		return nil, fmt.Errorf("error reading private key: %w", err)
+	}
+	return key, nil
+}
+
+// loadEncrypted returns the encrypted number.Number stored into the file
+// provided, or into the stdin.
+func loadEncrypted(path string, stdin io.Reader) (*number.Number, error) {
+	var num = new(number.Number)
+	if err := readJSON(path, stdin, num); err != nil {
+		return nil, fmt.Errorf("error reading encrypted number: %w", err)
+	} else if !num.IsEncrypted() {
+		return nil, errors.New("the number provided is not encrypted")
+	}
+	return num, nil
+}
+
+// parseValue returns the plain number.Number of the only positional argument
+// of the flags provided.
+func parseValue(flags *flag.FlagSet) (*number.Number, error) {
+	if flags.NArg() != 1 {
+		return nil, errors.New("one plain value is required")
+	}
+	return new(number.Number).SetString(flags.Arg(0))
+}
+
+// keygen generates a new paillier.PrivateKey and writes it encrypted under the
+// passphrase provided, or in clear with
+// paillier.PrivateKey.ExportInsecurePlaintext if it is explicitly requested.
+func keygen(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags = flag.NewFlagSet("keygen", flag.ContinueOnError)
+	var size = flags.Int("size", 1024, "size in bits of the prime factors of the key")
+	var out = flags.String("out", "", "output file of the private key")
+	var passphrasePath = flags.String("passphrase-file", "", "file with the passphrase to encrypt the private key")
+	var insecure = flags.Bool("insecure-plaintext", false, "write the private key without encryption")
+	if err := flags.Parse(args); err != nil {
+		return err
+	} else if *passphrasePath == "" && !*insecure {
+		return errors.New("a passphrase file is required to encrypt the private key (-passphrase-file), " +
+			"use -insecure-plaintext to write it in clear")
+	} else if *passphrasePath != "" && *insecure {
+		return errors.New("-passphrase-file and -insecure-plaintext are mutually exclusive")
+	}
+
+	var key, err = paillier.NewKeys(*size)
+	if err != nil {
+		return err
+	}
+
+	var passphrase, exported []byte
+	if *insecure {
+		if exported, err = key.ExportInsecurePlaintext(); err != nil {
+			return err
+		}
+		return writeOutput(*out, stdout, json.RawMessage(exported), 0600)
+	} else if passphrase, err = readPassphrase(*passphrasePath); err != nil {
+		return err
+	} else if exported, err = key.Export(passphrase); err != nil {
+		return err
+	}
+	return writeOutput(*out, stdout, json.RawMessage(exported), 0600)
+}
+
+// pubkey extracts the paillier.PublicKey of a paillier.PrivateKey and writes
+// it.
+func pubkey(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags = flag.NewFlagSet("pubkey", flag.ContinueOnError)
+	var keyPath = flags.String("key", "", "private key file")
+	var passphrasePath = flags.String("passphrase-file", "", "file with the passphrase of the private key")
+	var out = flags.String("out", "", "output file of the public key")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var key, err = loadPrivateKey(*keyPath, *passphrasePath)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, stdout, key.PubKey, 0644)
+}
+
+// encrypt encrypts the plain value provided and writes it.
+func encrypt(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags = flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	var pubKeyPath = flags.String("pubkey", "", "public key file")
+	var out = flags.String("out", "", "output file of the encrypted number")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var pubKey, err = loadPublicKey(*pubKeyPath)
+	if err != nil {
+		return err
+	}
+
+	var value, encrypted *number.Number
+	if value, err = parseValue(flags); err != nil {
+		return err
+	} else if encrypted, err = sdk.NewClient(nil, pubKey).Encrypt(value); err != nil {
+		return err
+	}
+	return writeOutput(*out, stdout, encrypted, 0644)
+}
+
+// decrypt decrypts the encrypted number provided and writes its plain
+// decimal value.
+func decrypt(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags = flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	var keyPath = flags.String("key", "", "private key file")
+	var passphrasePath = flags.String("passphrase-file", "", "file with the passphrase of the private key")
+	var in = flags.String("in", "", "input file of the encrypted number")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var key, err = loadPrivateKey(*keyPath, *passphrasePath)
+	if err != nil {
+		return err
+	}
+
+	var encrypted, decrypted *number.Number
+	if encrypted, err = loadEncrypted(*in, stdin); err != nil {
+		return err
+	} else if decrypted, err = sdk.NewClient(key, key.PubKey).Decrypt(encrypted); err != nil {
+		return err
+	} else if decrypted, err = decrypted.Normalize(); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, decrypted)
+	return err
+}
+
+// operation returns the command that computes the sdk operation provided
+// between an encrypted number and a plain value. The add operation also
+// accepts another encrypted number instead of the plain value.
+func operation(name string) command {
+	return func(args []string, stdin io.Reader, stdout io.Writer) error {
+		var flags = flag.NewFlagSet(name, flag.ContinueOnError)
+		var pubKeyPath = flags.String("pubkey", "", "public key file")
+		var in = flags.String("in", "", "input file of the encrypted number")
+		var out = flags.String("out", "", "output file of the encrypted result")
+		var with *string
+		if name == "add" {
+			with = flags.String("with", "", "file of another encrypted number to add")
+		}
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		var pubKey, err = loadPublicKey(*pubKeyPath)
+		if err != nil {
+			return err
+		}
+
+		var encrypted, result *number.Number
+		if encrypted, err = loadEncrypted(*in, stdin); err != nil {
+			return err
+		}
+
+		if with != nil && *with != "" {
+			var other *number.Number
+			if other, err = loadEncrypted(*with, nil); err != nil {
+				return err
+			} else if result, err = sdk.AddEncrypted(pubKey, encrypted, other); err != nil {
+				return err
+			}
+			return writeOutput(*out, stdout, result, 0644)
+		}
+
+		var value *number.Number
+		if value, err = parseValue(flags); err != nil {
+			return err
+		}
+
+		switch name {
+		case "add":
+			result, err = sdk.Add(pubKey, encrypted, value)
+		case "sub":
+			result, err = sdk.Sub(pubKey, encrypted, value)
+		case "mul":
+			result, err = sdk.Mul(pubKey, encrypted, value)
+		case "div":
+			if value.Value.Sign() == 0 {
+				return errors.New("division by zero")
+			}
+			result, err = sdk.Div(pubKey, encrypted, value)
+		}
+		if err != nil {
+			return err
+		}
+		return writeOutput(*out, stdout, result, 0644)
+	}
+}
+
+// sum adds all the encrypted numbers provided, as files or as a stream from
+// stdin, and writes the encrypted result.
+func sum(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags = flag.NewFlagSet("sum", flag.ContinueOnError)
+	var pubKeyPath = flags.String("pubkey", "", "public key file")
+	var out = flags.String("out", "", "output file of the encrypted result")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var pubKey, err = loadPublicKey(*pubKeyPath)
+	if err != nil {
+		return err
+	}
+
+	var inputs []*number.Number
+	if flags.NArg() > 0 {
+		for _, path := range flags.Args() {
+			var encrypted *number.Number
+			if encrypted, err = loadEncrypted(path, nil); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			inputs = append(inputs, encrypted)
+		}
+	} else {
+		var data []byte
+		if data, err = io.ReadAll(stdin); err != nil {
+			return err
+		}
+
+		var decoder = json.NewDecoder(bytes.NewReader(data))
+		for decoder.More() {
+			var encrypted = new(number.Number)
+			if err = decoder.Decode(encrypted); err != nil {
+				return fmt.Errorf("error reading encrypted number: %w", err)
+			} else if !encrypted.IsEncrypted() {
+				return errors.New("the number provided is not encrypted")
+			}
+			inputs = append(inputs, encrypted)
+		}
+	}
+
+	if len(inputs) == 0 {
+		return errors.New("no encrypted numbers provided")
+	}
+
+	var result = inputs[0]
+	for _, input := range inputs[1:] {
+		if result, err = sdk.AddEncrypted(pubKey, result, input); err != nil {
+			return err
+		}
+	}
+	return writeOutput(*out, stdout, result, 0644)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lucasmenendez/gopaillier/pkg/csv"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// readTable returns the csv.Table stored into the file provided, or into the
+// stdin.
+func readTable(path string, stdin io.Reader) (*csv.Table, error) {
+	var data, err = readInput(path, stdin)
+	if err != nil {
+		return nil, err
+	}
+	return csv.Read(bytes.NewReader(data))
+}
+
+// writeTable writes the provided csv.Table into the file provided, or into the
+// stdout if the path is empty or "-".
+func writeTable(path string, stdout io.Writer, table *csv.Table) error {
+	if path == "" || path == "-" {
+		return table.Write(stdout)
+	}
+
+	var output = new(bytes.Buffer)
+	if err := table.Write(output); err != nil {
+		return err
+	}
+	return os.WriteFile(path, output.Bytes(), 0644)
+}
+
+// splitList returns the not empty items of the provided comma separated list.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// csvEncrypt encrypts the selected columns of a CSV table and writes it.
+func csvEncrypt(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags = flag.NewFlagSet("csv-encrypt", flag.ContinueOnError)
+	var pubKeyPath = flags.String("pubkey", "", "public key file")
+	var columns = flags.String("columns", "", "comma separated list of columns to encrypt")
+	var in = flags.String("in", "", "input CSV file")
+	var out = flags.String("out", "", "output CSV file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	} else if len(splitList(*columns)) == 0 {
+		return errors.New("at least one column to encrypt is required (-columns)")
+	}
+
+	var pubKey, err = loadPublicKey(*pubKeyPath)
+	if err != nil {
+		return err
+	}
+
+	var table *csv.Table
+	if table, err = readTable(*in, stdin); err != nil {
+		return err
+	} else if table, err = csv.EncryptColumns(pubKey, table, splitList(*columns)); err != nil {
+		return err
+	}
+	return writeTable(*out, stdout, table)
+}
+
+// csvAggregate aggregates the encrypted columns of a CSV table grouped by its
+// plain columns and writes the resulting table.
+func csvAggregate(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags = flag.NewFlagSet("csv-aggregate", flag.ContinueOnError)
+	var pubKeyPath = flags.String("pubkey", "", "public key file")
+	var groupBy = flags.String("group", "", "comma separated list of plain columns to group by")
+	var exprs = flags.String("agg", "", "comma separated list of aggregations, e.g. sum(a),count(a),mean(b)")
+	var in = flags.String("in", "", "input CSV file")
+	var out = flags.String("out", "", "output CSV file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var pubKey, err = loadPublicKey(*pubKeyPath)
+	if err != nil {
+		return err
+	}
+
+	var aggs []csv.Aggregation
+	for _, expr := range splitList(*exprs) {
+		var agg csv.Aggregation
+		if agg, err = csv.ParseAggregation(expr); err != nil {
+			return err
+		}
+		aggs = append(aggs, agg)
+	}
+
+	var table *csv.Table
+	if table, err = readTable(*in, stdin); err != nil {
+		return err
+	} else if table, err = csv.Aggregate(pubKey, table, splitList(*groupBy), aggs); err != nil {
+		return err
+	}
+	return writeTable(*out, stdout, table)
+}
+
+// csvDecrypt decrypts all the encrypted cells of a CSV table and writes it.
+func csvDecrypt(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags = flag.NewFlagSet("csv-decrypt", flag.ContinueOnError)
+	var keyPath = flags.String("key", "", "private key file")
+	var passphrasePath = flags.String("passphrase-file", "", "file with the passphrase of the private key")
+	var in = flags.String("in", "", "input CSV file")
+	var out = flags.String("out", "", "output CSV file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var key, err = loadPrivateKey(*keyPath, *passphrasePath)
+	if err != nil {
+		return err
+	}
+
+	var table *csv.Table
+	if table, err = readTable(*in, stdin); err != nil {
+		return err
+	} else if table, err = csv.DecryptTable(sdk.NewClient(key, key.PubKey), table); err != nil {
+		return err
+	}
+	return writeTable(*out, stdout, table)
+}
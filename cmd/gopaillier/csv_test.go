@@ -0,0 +1,24 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCSV(t *testing.T) {
+	var dir = t.TempDir()
+	var key = filepath.Join(dir, "key.json")
+	var pubKey = filepath.Join(dir, "pubkey.json")
+	exec(t, "", "keygen", "-size", "128", "-out", key, "-insecure-plaintext")
+	exec(t, "", "pubkey", "-key", key, "-out", pubKey)
+
+	var input = "team,points\nred,1.5\nblue,2\nred,3\n"
+	var encrypted = exec(t, input, "csv-encrypt", "-pubkey", pubKey, "-columns", "points")
+	var aggregated = exec(t, encrypted, "csv-aggregate", "-pubkey", pubKey, "-group", "team", "-agg", "sum(points), count(points), mean(points)")
+	var result = exec(t, aggregated, "csv-decrypt", "-key", key)
+
+	var expected = "team,sum(points),count(points),mean(points)\nred,4.5,2,2.25\nblue,2,1,2\n"
+	if result != expected {
+		t.Fatalf("expected %q, got %q", expected, result)
+	}
+}
@@ -0,0 +1,96 @@
+// Command gopaillier allows to generate Paillier keys, encrypt and decrypt
+// numbers, and compute operations over encrypted numbers from the command
+// line. Keys and encrypted numbers are read and written using the JSON
+// serialization of paillier.PrivateKey, paillier.PublicKey and number.Number,
+// from files or from stdin/stdout.
+//
+// Usage:
+//
+//	gopaillier keygen  [-size 1024] [-out key.json] (-passphrase-file pass.txt | -insecure-plaintext)
+//	gopaillier pubkey  -key key.json [-out pubkey.json] [-passphrase-file pass.txt]
+//	gopaillier encrypt -pubkey pubkey.json [-out a.json] <value>
+//	gopaillier decrypt -key key.json [-in a.json] [-passphrase-file pass.txt]
+//	gopaillier add     -pubkey pubkey.json [-in a.json] [-out c.json] (<value> | -with b.json)
+//	gopaillier sub     -pubkey pubkey.json [-in a.json] [-out c.json] <value>
+//	gopaillier mul     -pubkey pubkey.json [-in a.json] [-out c.json] <value>
+//	gopaillier div     -pubkey pubkey.json [-in a.json] [-out c.json] <value>
+//	gopaillier sum     -pubkey pubkey.json [-out c.json] [a.json b.json ...]
+//
+//	gopaillier csv-encrypt   -pubkey pubkey.json -columns a,b [-in t.csv] [-out e.csv]
+//	gopaillier csv-aggregate -pubkey pubkey.json -group k -agg "sum(a),count(a),mean(b)" [-in e.csv] [-out r.csv]
+//	gopaillier csv-decrypt   -key key.json [-in r.csv] [-out d.csv] [-passphrase-file pass.txt]
+//
+// If -in or -out are not provided, stdin and stdout are used. The sum command
+// reads a stream of encrypted numbers from stdin if no files are provided. The
+// csv commands encrypt the selected columns of a CSV table, aggregate them
+// grouped by plain columns and decrypt the resulting table. The keygen command
+// writes the private key encrypted under the passphrase of -passphrase-file
+// with paillier.PrivateKey.Export, unless -insecure-plaintext is provided to
+// write it in clear with paillier.PrivateKey.ExportInsecurePlaintext. The
+// commands that read the private key import it with paillier.ImportPrivateKey
+// if -passphrase-file is provided, or with paillier.ImportInsecurePlaintext
+// otherwise. Negative plain values must be preceded by "--" to not be parsed
+// as flags, for example:
+//
+//	gopaillier mul -pubkey pubkey.json -in a.json -- -3
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// command defines the signature of the functions that implement the
+// subcommands of the CLI.
+type command func(args []string, stdin io.Reader, stdout io.Writer) error
+
+var commands = map[string]command{
+	"keygen":  keygen,
+	"pubkey":  pubkey,
+	"encrypt": encrypt,
+	"decrypt": decrypt,
+	"add":     operation("add"),
+	"sub":     operation("sub"),
+	"mul":     operation("mul"),
+	"div":     operation("div"),
+	"sum":     sum,
+
+	"csv-encrypt":   csvEncrypt,
+	"csv-aggregate": csvAggregate,
+	"csv-decrypt":   csvDecrypt,
+}
+
+// usage returns the help message of the CLI.
+func usage() string {
+	var names = make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("usage: gopaillier <command> [flags]\ncommands: %s", strings.Join(names, ", "))
+}
+
+// run executes the subcommand requested into the provided args with the
+// provided stdin and stdout.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage())
+	}
+
+	var cmd, ok = commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command '%s'\n%s", args[0], usage())
+	}
+	return cmd(args[1:], stdin, stdout)
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
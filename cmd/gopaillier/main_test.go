@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// exec runs the CLI with the provided args and stdin and returns the content
+// written into the stdout.
+func exec(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+
+	var stdout = new(bytes.Buffer)
+	if err := run(args, strings.NewReader(stdin), stdout); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	return stdout.String()
+}
+
+func TestRun(t *testing.T) {
+	var dir = t.TempDir()
+	var key = filepath.Join(dir, "key.json")
+	var pubKey = filepath.Join(dir, "pubkey.json")
+	var a = filepath.Join(dir, "a.json")
+	var b = filepath.Join(dir, "b.json")
+
+	exec(t, "", "keygen", "-size", "128", "-out", key, "-insecure-plaintext")
+	exec(t, "", "pubkey", "-key", key, "-out", pubKey)
+	exec(t, "", "encrypt", "-pubkey", pubKey, "-out", a, "1.5")
+	exec(t, "", "encrypt", "-pubkey", pubKey, "-out", b, "--", "-4")
+
+	var tests = []struct {
+		args     []string
+		stdin    string
+		expected string
+	}{
+		{[]string{"add", "-pubkey", pubKey, "-in", a, "2"}, "", "3.5"},
+		{[]string{"add", "-pubkey", pubKey, "-in", a, "-with", b}, "", "-2.5"},
+		{[]string{"sub", "-pubkey", pubKey, "-in", a, "0.5"}, "", "1"},
+		{[]string{"mul", "-pubkey", pubKey, "-in", a, "--", "-3"}, "", "-4.5"},
+		{[]string{"div", "-pubkey", pubKey, "-in", b, "2"}, "", "-2"},
+		{[]string{"sum", "-pubkey", pubKey, a, b, a}, "", "-1"},
+	}
+
+	for _, test := range tests {
+		var encrypted = exec(t, test.stdin, test.args...)
+		if result := exec(t, encrypted, "decrypt", "-key", key); strings.TrimSpace(result) != test.expected {
+			t.Fatalf("%s: expected %s, got %s", test.args[0], test.expected, result)
+		}
+	}
+
+	// sum a stream of encrypted numbers from stdin
+	var stream = exec(t, "", "encrypt", "-pubkey", pubKey, "10") + exec(t, "", "encrypt", "-pubkey", pubKey, "0.25")
+	var encrypted = exec(t, stream, "sum", "-pubkey", pubKey)
+	if result := exec(t, encrypted, "decrypt", "-key", key); strings.TrimSpace(result) != "10.25" {
+		t.Fatalf("expected 10.25, got %s", result)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	var dir = t.TempDir()
+	var key = filepath.Join(dir, "key.json")
+	var pubKey = filepath.Join(dir, "pubkey.json")
+	exec(t, "", "keygen", "-size", "128", "-out", key, "-insecure-plaintext")
+	exec(t, "", "pubkey", "-key", key, "-out", pubKey)
+	var encrypted = exec(t, "", "encrypt", "-pubkey", pubKey, "1")
+
+	var tests = []struct {
+		args  []string
+		stdin string
+	}{
+		{[]string{}, ""},
+		{[]string{"unknown"}, ""},
+		{[]string{"keygen", "-size", "128"}, ""},
+		{[]string{"keygen", "-size", "128", "-insecure-plaintext", "-passphrase-file", key}, ""},
+		{[]string{"encrypt", "1"}, ""},
+		{[]string{"encrypt", "-pubkey", pubKey, "abc"}, ""},
+		{[]string{"decrypt", "-key", key}, `{"value":"1","exp":"0","encrypted":false}`},
+		{[]string{"div", "-pubkey", pubKey, "0"}, encrypted},
+		{[]string{"mul", "-pubkey", pubKey}, encrypted},
+		{[]string{"sum", "-pubkey", pubKey}, ""},
+	}
+
+	for _, test := range tests {
+		var stdout = new(bytes.Buffer)
+		if err := run(test.args, strings.NewReader(test.stdin), stdout); err == nil {
+			t.Fatalf("%v: expected error, got nil", test.args)
+		}
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	var dir = t.TempDir()
+	var key = filepath.Join(dir, "key.json")
+	var pubKey = filepath.Join(dir, "pubkey.json")
+	var passphrase = filepath.Join(dir, "pass.txt")
+	var wrong = filepath.Join(dir, "wrong.txt")
+	os.WriteFile(passphrase, []byte("secret\n"), 0600)
+	os.WriteFile(wrong, []byte("other\n"), 0600)
+
+	exec(t, "", "keygen", "-size", "128", "-out", key, "-passphrase-file", passphrase)
+	if data, _ := os.ReadFile(key); strings.Contains(string(data), `"d"`) {
+		t.Fatal("expected the private key to be encrypted")
+	}
+
+	exec(t, "", "pubkey", "-key", key, "-out", pubKey, "-passphrase-file", passphrase)
+	var encrypted = exec(t, "", "encrypt", "-pubkey", pubKey, "12.5")
+	if result := exec(t, encrypted, "decrypt", "-key", key, "-passphrase-file", passphrase); strings.TrimSpace(result) != "12.5" {
+		t.Fatalf("expected 12.5, got %s", result)
+	}
+
+	for _, args := range [][]string{
+		{"decrypt", "-key", key},
+		{"decrypt", "-key", key, "-passphrase-file", wrong},
+		{"pubkey", "-key", key, "-passphrase-file", filepath.Join(dir, "missing.txt")},
+	} {
+		if err := run(args, strings.NewReader(encrypted), new(bytes.Buffer)); err == nil {
+			t.Fatalf("%v: expected error, got nil", args)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+	"github.com/lucasmenendez/gopaillier/pkg/stats"
+)
+
+func main() {
+	var aClient, _ = sdk.InitClient(512)
+
+	// Encode and encrypt every number record
+	var numbers = []int64{4, 27, 2, 39, 25, 37, 85, 17, 15, 21, 58, 27, 77, 4, 91, 64, 90, 78, 48, 43, 40, 55, 56, 57, 92, 50, 78, 6, 42, 64, 19, 14, 7, 61, 87, 86, 73, 82, 72, 48, 28, 76, 49, 65, 34, 81, 40, 10, 83, 70, 30, 55, 35, 85, 45, 6, 41, 24, 42, 61, 34, 54, 88, 14, 99, 23, 9, 69, 36, 18, 59, 49, 48, 14, 13, 11, 42, 80, 91, 50, 35, 26, 90, 60, 41, 26, 85, 84, 9, 79, 30, 81, 51, 90, 16, 21, 13, 69, 57, 71}
+	var encryptedNumbers = make([]*number.Number, len(numbers))
+	var rawSumatory int64
+	for i, num := range numbers {
+		rawSumatory += num
+		encryptedNumbers[i], _ = aClient.Encrypt(new(number.Number).SetInt(num))
+	}
+
+	// Get the encrypted sum and mean of the encrypted records
+	var encryptedSumatory, _, _ = stats.Sum(aClient.PubKey, encryptedNumbers)
+	var encryptedMean, count, _ = stats.Mean(aClient.PubKey, encryptedNumbers)
+
+	// Decrypt it and decode it
+	var decryptedMean, _ = encryptedMean.Decrypt(aClient, stats.DefaultDecimals)
+	var decodedMean = decryptedMean.Float()
+
+	// Calc raw mean
+	var mean = float64(rawSumatory) / float64(len(numbers))
+
+	// Make some prints
+	fmt.Printf("\nPerform mean of %d numbers: \n%v\n", count, numbers)
+	fmt.Printf("\t- Raw sum: %d, Encrypted sum: %v\n", rawSumatory, encryptedSumatory.Value)
+	fmt.Printf("\t- Raw mean: %.2f, Decrypted mean: %.2f\n\n", mean, decodedMean)
+}
@@ -10,7 +10,7 @@ import (
 func main() {
 	// Set A plain data and convert to Number
 	var aData float64 = 12.2
-	var aNum *number.Number = new(number.Number).SetFloat(aData)
+	var aNum, _ = new(number.Number).SetFloat(aData)
 
 	// Instance client and encrypt data and send encrypted data to a third
 	// party with the public key.
@@ -19,14 +19,14 @@ func main() {
 
 	// Set B plain data and convert to Number
 	var bData float64 = -0.00005
-	var bNum *number.Number = new(number.Number).SetFloat(bData)
+	var bNum, _ = new(number.Number).SetFloat(bData)
 
 	// Perform the multiplication between the encrypted received Number and the
 	// B Number using the received public key.
-	var sumEncrypted, _ = sdk.Add(aClient.Key.PubKey, aEncrypted, bNum)
-	var subEncrypted, _ = sdk.Sub(aClient.Key.PubKey, aEncrypted, bNum)
-	var mulEncrypted, _ = sdk.Mul(aClient.Key.PubKey, aEncrypted, bNum)
-	var divEncrypted, _ = sdk.Div(aClient.Key.PubKey, aEncrypted, bNum)
+	var sumEncrypted, _ = sdk.Add(aClient.PubKey, aEncrypted, bNum)
+	var subEncrypted, _ = sdk.Sub(aClient.PubKey, aEncrypted, bNum)
+	var mulEncrypted, _ = sdk.Mul(aClient.PubKey, aEncrypted, bNum)
+	var divEncrypted, _ = sdk.Div(aClient.PubKey, aEncrypted, bNum)
 
 	// Send the encrypted Mul to A to decrypt the value and print the plain
 	// Mul.
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+	"github.com/lucasmenendez/gopaillier/pkg/voting"
+)
+
+func main() {
+	// The keyholder creates the key pair and publishes the public key.
+	key, err := paillier.NewKeys(512)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// The election is created with the public key and the candidates. Every
+	// candidate count is stored into a slot of 16 bits of the same ciphertext.
+	var candidates = []string{"Alice", "Bob", "Carol"}
+	election, err := voting.NewElection(key.PubKey, candidates, voting.PerSlot, 16)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Every voter encrypts its choice into a ballot with the proofs of its
+	// validity, bound to its identifier, and casts it. The ballots are
+	// verified and added to the encrypted tally without decrypting them.
+	var votes = []int{0, 2, 1, 2, 2, 0, 2, 1, 2}
+	for i, vote := range votes {
+		var ballot, err = election.Vote(fmt.Sprintf("voter-%d", i), vote)
+		if err != nil {
+			log.Fatalln(err)
+		} else if err = election.Cast(ballot); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	// The keyholder decrypts the tally and publishes the result with the
+	// proofs of the correct decryption, that anyone can verify.
+	result, err := election.Decrypt(key)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Printf("Encrypted tally: %d\n\n", election.Tally[0])
+	fmt.Printf("Result of %d votes (verified: %t):\n", result.Votes, election.VerifyResult(result))
+	for i, candidate := range result.Candidates {
+		fmt.Printf("\t- %s: %d\n", candidate, result.Counts[i])
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/lucasmenendez/gopaillier
 
 go 1.19
+
+require golang.org/x/crypto v0.24.0
@@ -0,0 +1,144 @@
+package aggregation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+// Struct Client allows to interact with an aggregation Server through HTTP.
+// The Token is only required by the keyholder to create and close the rounds
+// and to get their results.
+type Client struct {
+	URL   string
+	Token string
+	HTTP  *http.Client
+}
+
+// Function NewClient returns a new Client for the aggregation Server available
+// on the URL provided, using the http.DefaultClient.
+func NewClient(serverURL string) *Client {
+	return &Client{URL: strings.TrimRight(serverURL, "/"), HTTP: http.DefaultClient}
+}
+
+// request sends a request to the Server with the method, path and body
+// provided, and decodes the response into the result provided. It returns an
+// error if the request fails or if the Server responds with an error.
+func (client *Client) request(method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		var data, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	var req, err = http.NewRequest(method, client.URL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+client.Token)
+	}
+
+	var res *http.Response
+	if res, err = client.HTTP.Do(req); err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		var serverErr map[string]string
+		if err = json.NewDecoder(res.Body).Decode(&serverErr); err != nil || serverErr["error"] == "" {
+			return fmt.Errorf("server error: %s", res.Status)
+		}
+		return errors.New(serverErr["error"])
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// roundPath returns the path of the endpoint provided for the round provided.
+func roundPath(id, endpoint string) string {
+	var path = "/rounds/" + url.PathEscape(id)
+	if endpoint != "" {
+		path += "/" + endpoint
+	}
+	return path
+}
+
+// Function PublicKey returns the paillier.PublicKey published by the Server.
+func (client *Client) PublicKey() (*paillier.PublicKey, error) {
+	var pubKey = new(paillier.PublicKey)
+	if err := client.request(http.MethodGet, "/pubkey", nil, pubKey); err != nil {
+		return nil, err
+	}
+	return pubKey, nil
+}
+
+// Function CreateRound creates a new round with the identifier provided and
+// returns its Status. It requires the keyholder Token.
+func (client *Client) CreateRound(id string) (*Status, error) {
+	var status = new(Status)
+	if err := client.request(http.MethodPost, "/rounds", Status{ID: id}, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Function Status returns the Status of the round provided.
+func (client *Client) Status(id string) (*Status, error) {
+	var status = new(Status)
+	if err := client.request(http.MethodGet, roundPath(id, ""), nil, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Function Submit sends the encrypted number.Number provided to the round
+// provided and returns its updated Status. It returns an error if the reading
+// is not encrypted.
+func (client *Client) Submit(id string, reading *number.Number) (*Status, error) {
+	if !reading.IsEncrypted() {
+		return nil, errors.New("the reading must be encrypted")
+	}
+
+	var status = new(Status)
+	if err := client.request(http.MethodPost, roundPath(id, "numbers"), reading, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Function Close closes the round provided and returns its final Status. It
+// requires the keyholder Token.
+func (client *Client) Close(id string) (*Status, error) {
+	var status = new(Status)
+	if err := client.request(http.MethodPost, roundPath(id, "close"), nil, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Function Result returns the encrypted Result of the closed round provided.
+// It requires the keyholder Token.
+func (client *Client) Result(id string) (*Result, error) {
+	var result = new(Result)
+	if err := client.request(http.MethodGet, roundPath(id, "result"), nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package aggregation
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+func TestClient(t *testing.T) {
+	var server, _ = NewServer(key.PubKey, "secret")
+	var testServer = httptest.NewServer(server)
+	defer testServer.Close()
+
+	var keyholder = NewClient(testServer.URL)
+	keyholder.Token = "secret"
+	if _, err := keyholder.CreateRound("round-1"); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if _, err = keyholder.CreateRound("round-1"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// every client gets the public key from the server, encrypts its reading
+	// and submits it concurrently
+	var readings = []float64{12.5, -3.25, 100, 0.125, 7}
+	var wg sync.WaitGroup
+	var errs = make(chan error, len(readings))
+	for _, reading := range readings {
+		wg.Add(1)
+		go func(reading float64) {
+			defer wg.Done()
+
+			var client = NewClient(testServer.URL)
+			var pubKey, err = client.PublicKey()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			var encoded, _ = new(number.Number).SetFloat(reading)
+			var encrypted *number.Number
+			if encrypted, err = sdk.NewClient(nil, pubKey).Encrypt(encoded); err != nil {
+				errs <- err
+			} else if _, err = client.Submit("round-1", encrypted); err != nil {
+				errs <- err
+			}
+		}(reading)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var client = NewClient(testServer.URL)
+	if _, err := client.Submit("round-1", new(number.Number).SetInt(1)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if status, err := client.Status("round-1"); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if status.Count != len(readings) || status.Closed {
+		t.Fatalf("unexpected status %+v", status)
+	} else if _, err = client.CreateRound("round-2"); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = client.Close("round-1"); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = keyholder.Close("round-1"); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if _, err = client.Result("round-1"); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = client.Status("unknown"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var result, err = keyholder.Result("round-1")
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if result.Count != len(readings) || !result.Sum.IsEncrypted() {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	var decrypted, _ = sdk.NewClient(key, key.PubKey).Decrypt(result.Sum)
+	if sum := decrypted.Float(); sum != 116.375 {
+		t.Fatalf("expected 116.375, got %v", sum)
+	}
+}
@@ -0,0 +1,256 @@
+// Package aggregation provides an HTTP service to aggregate encrypted readings
+// sent by many clients, and a Go client library to interact with it. The
+// server publishes the paillier.PublicKey of the keyholder, accepts encrypted
+// number.Number's grouped into rounds, adds them homomorphically as they
+// arrive, and exposes the encrypted sum of every closed round to the
+// keyholder, who is the only one able to decrypt it. The server never sees
+// any plain reading.
+//
+// The service exposes the following endpoints:
+//
+//	GET  /pubkey               returns the public key.
+//	POST /rounds               creates a new round: {"id": "..."}, only to the
+//	                           keyholder.
+//	GET  /rounds/{id}          returns the status of the round.
+//	POST /rounds/{id}/numbers  submits an encrypted number.Number to the round.
+//	POST /rounds/{id}/close    closes the round, rejecting new submissions,
+//	                           only to the keyholder.
+//	GET  /rounds/{id}/result   returns the encrypted sum of a closed round,
+//	                           only to the keyholder.
+package aggregation
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// maxBodySize is the maximum size in bytes of the requests bodies.
+const maxBodySize = 1 << 20
+
+// maxExp is the maximum absolute exponent of the readings accepted. Adding two
+// readings aligns their exponents multiplying by 10 raised to their
+// difference, so the exponents must be bounded to keep that cost low.
+const maxExp = 64
+
+var bMaxExp = big.NewInt(maxExp)
+
+// Struct Status contains the public information of a round: its identifier,
+// the number of readings received and if it is closed.
+type Status struct {
+	ID     string `json:"id"`
+	Count  int    `json:"count"`
+	Closed bool   `json:"closed"`
+}
+
+// Struct Result contains the encrypted sum of the readings of a round and the
+// number of readings added.
+type Result struct {
+	ID    string         `json:"id"`
+	Count int            `json:"count"`
+	Sum   *number.Number `json:"sum"`
+}
+
+// round contains the state of an aggregation round.
+type round struct {
+	status Status
+	sum    *number.Number
+}
+
+// Struct Server implements an http.Handler that aggregates the encrypted
+// readings of the clients into rounds using the paillier.PublicKey provided.
+// The Token is the secret shared with the keyholder to create and close the
+// rounds and to access to their results.
+type Server struct {
+	PubKey *paillier.PublicKey
+	Token  string
+
+	mtx    sync.Mutex
+	rounds map[string]*round
+}
+
+// Function NewServer returns a new Server with the paillier.PublicKey and the
+// keyholder token provided. It returns an error if the token is empty.
+func NewServer(pubKey *paillier.PublicKey, token string) (*Server, error) {
+	if token == "" {
+		return nil, errors.New("keyholder token is required")
+	}
+
+	return &Server{PubKey: pubKey, Token: token, rounds: make(map[string]*round)}, nil
+}
+
+// Function ServeHTTP implements the http.Handler interface, routing the
+// requests to the endpoints of the service.
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var parts = strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "pubkey" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, server.PubKey)
+	case len(parts) == 1 && parts[0] == "rounds" && r.Method == http.MethodPost:
+		if server.authorize(w, r) {
+			server.createRound(w, r)
+		}
+	case len(parts) == 2 && parts[0] == "rounds" && r.Method == http.MethodGet:
+		server.roundStatus(w, parts[1])
+	case len(parts) == 3 && parts[0] == "rounds" && parts[2] == "numbers" && r.Method == http.MethodPost:
+		server.submit(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "rounds" && parts[2] == "close" && r.Method == http.MethodPost:
+		if server.authorize(w, r) {
+			server.closeRound(w, parts[1])
+		}
+	case len(parts) == 3 && parts[0] == "rounds" && parts[2] == "result" && r.Method == http.MethodGet:
+		if server.authorize(w, r) {
+			server.result(w, parts[1])
+		}
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// authorize returns if the request includes the keyholder token, comparing it
+// in constant time, or writes an unauthorized error into the response.
+func (server *Server) authorize(w http.ResponseWriter, r *http.Request) bool {
+	var expected = []byte("Bearer " + server.Token)
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+		writeError(w, http.StatusUnauthorized, errors.New("keyholder token required"))
+		return false
+	}
+	return true
+}
+
+// createRound creates a new empty round with the identifier provided into the
+// request body.
+func (server *Server) createRound(w http.ResponseWriter, r *http.Request) {
+	var req Status
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if req.ID == "" || strings.Contains(req.ID, "/") {
+		writeError(w, http.StatusBadRequest, errors.New("invalid round id"))
+		return
+	}
+
+	server.mtx.Lock()
+	defer server.mtx.Unlock()
+	if _, exists := server.rounds[req.ID]; exists {
+		writeError(w, http.StatusConflict, fmt.Errorf("round '%s' already exists", req.ID))
+		return
+	}
+
+	var created = &round{status: Status{ID: req.ID}}
+	server.rounds[req.ID] = created
+	writeJSON(w, http.StatusCreated, created.status)
+}
+
+// roundStatus returns the status of the round requested.
+func (server *Server) roundStatus(w http.ResponseWriter, id string) {
+	server.mtx.Lock()
+	defer server.mtx.Unlock()
+	if current, ok := server.getRound(w, id); ok {
+		writeJSON(w, http.StatusOK, current.status)
+	}
+}
+
+// submit adds the encrypted number.Number of the request body to the sum of
+// the round requested.
+func (server *Server) submit(w http.ResponseWriter, r *http.Request, id string) {
+	var reading = new(number.Number)
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(reading); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if !reading.IsEncrypted() {
+		writeError(w, http.StatusBadRequest, errors.New("the reading must be encrypted"))
+		return
+	} else if reading.Value.Sign() <= 0 || reading.Value.Cmp(server.PubKey.Nsq) >= 0 {
+		writeError(w, http.StatusBadRequest, errors.New("invalid encrypted reading"))
+		return
+	} else if reading.Exp.CmpAbs(bMaxExp) > 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("the reading exponent must be in [-%d, %d]", maxExp, maxExp))
+		return
+	}
+
+	server.mtx.Lock()
+	defer server.mtx.Unlock()
+	var current, ok = server.getRound(w, id)
+	if !ok {
+		return
+	} else if current.status.Closed {
+		writeError(w, http.StatusConflict, fmt.Errorf("round '%s' is closed", id))
+		return
+	}
+
+	if current.sum == nil {
+		current.sum = reading
+	} else {
+		var sum, err = sdk.AddEncrypted(server.PubKey, current.sum, reading)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		current.sum = sum
+	}
+
+	current.status.Count++
+	writeJSON(w, http.StatusOK, current.status)
+}
+
+// closeRound closes the round requested.
+func (server *Server) closeRound(w http.ResponseWriter, id string) {
+	server.mtx.Lock()
+	defer server.mtx.Unlock()
+	if current, ok := server.getRound(w, id); ok {
+		current.status.Closed = true
+		writeJSON(w, http.StatusOK, current.status)
+	}
+}
+
+// result returns the encrypted sum of the round requested if it is closed.
+func (server *Server) result(w http.ResponseWriter, id string) {
+	server.mtx.Lock()
+	defer server.mtx.Unlock()
+	var current, ok = server.getRound(w, id)
+	if !ok {
+		return
+	} else if !current.status.Closed {
+		writeError(w, http.StatusConflict, fmt.Errorf("round '%s' is not closed", id))
+		return
+	} else if current.sum == nil {
+		writeError(w, http.StatusConflict, fmt.Errorf("round '%s' has no readings", id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Result{id, current.status.Count, current.sum})
+}
+
+// getRound returns the round with the identifier provided or writes a not
+// found error into the response. The server mutex must be locked.
+func (server *Server) getRound(w http.ResponseWriter, id string) (*round, bool) {
+	var current, ok = server.rounds[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("round '%s' not found", id))
+	}
+	return current, ok
+}
+
+// writeJSON writes the provided body encoded as JSON into the response with
+// the provided status code.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes the provided error encoded as JSON into the response with
+// the provided status code.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,67 @@
+package aggregation
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+var key, _ = paillier.NewKeys(128)
+
+func TestNewServer(t *testing.T) {
+	if _, err := NewServer(key.PubKey, ""); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewServer(key.PubKey, "secret"); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	var server, _ = NewServer(key.PubKey, "secret")
+	var requests = []struct {
+		method, path, body, token string
+		status                    int
+	}{
+		{http.MethodGet, "/pubkey", "", "", http.StatusOK},
+		{http.MethodGet, "/unknown", "", "", http.StatusNotFound},
+		{http.MethodPost, "/rounds", `{"id": "r1"}`, "", http.StatusUnauthorized},
+		{http.MethodPost, "/rounds", `{"id": "r1"}`, "wrong", http.StatusUnauthorized},
+		{http.MethodPost, "/rounds", `{"id": ""}`, "secret", http.StatusBadRequest},
+		{http.MethodPost, "/rounds", `{"id": "a/b"}`, "secret", http.StatusBadRequest},
+		{http.MethodPost, "/rounds", `wrong`, "secret", http.StatusBadRequest},
+		{http.MethodPost, "/rounds", `{"id": "r1"}`, "secret", http.StatusCreated},
+		{http.MethodPost, "/rounds", `{"id": "r1"}`, "secret", http.StatusConflict},
+		{http.MethodGet, "/rounds/r1", "", "", http.StatusOK},
+		{http.MethodGet, "/rounds/r2", "", "", http.StatusNotFound},
+		{http.MethodPost, "/rounds/r2/numbers", `{"value": 10, "exp": 0, "encrypted": true}`, "", http.StatusNotFound},
+		{http.MethodPost, "/rounds/r1/numbers", `{"value": 10, "exp": 0}`, "", http.StatusBadRequest},
+		{http.MethodPost, "/rounds/r1/numbers", `{"value": 0, "exp": 0, "encrypted": true}`, "", http.StatusBadRequest},
+		{http.MethodPost, "/rounds/r1/numbers", `{"value": 10, "exp": -1000000000, "encrypted": true}`, "", http.StatusBadRequest},
+		{http.MethodPost, "/rounds/r1/numbers", `{"value": 10, "exp": 65, "encrypted": true}`, "", http.StatusBadRequest},
+		{http.MethodPost, "/rounds/r1/numbers", `{"value": 10, "exp": 0, "encrypted": true}`, "", http.StatusOK},
+		{http.MethodPost, "/rounds/r1/numbers", `{"value": 10, "exp": -64, "encrypted": true}`, "", http.StatusOK},
+		{http.MethodGet, "/rounds/r1/result", "", "secret", http.StatusConflict},
+		{http.MethodPost, "/rounds/r1/close", "", "", http.StatusUnauthorized},
+		{http.MethodPost, "/rounds/r1/close", "", "secret", http.StatusOK},
+		{http.MethodPost, "/rounds/r1/numbers", `{"value": 10, "exp": 0, "encrypted": true}`, "", http.StatusConflict},
+		{http.MethodGet, "/rounds/r1/result", "", "", http.StatusUnauthorized},
+		{http.MethodGet, "/rounds/r1/result", "", "wrong", http.StatusUnauthorized},
+		{http.MethodGet, "/rounds/r1/result", "", "secret", http.StatusOK},
+	}
+
+	for _, req := range requests {
+		var r = httptest.NewRequest(req.method, req.path, bytes.NewBufferString(req.body))
+		if req.token != "" {
+			r.Header.Set("Authorization", "Bearer "+req.token)
+		}
+
+		var w = httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		if w.Code != req.status {
+			t.Fatalf("%s %s: expected %d, got %d (%s)", req.method, req.path, req.status, w.Code, w.Body)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+// Package csv allows to encrypt selected numeric columns of a CSV table into
+// number.Number ciphertexts, to aggregate them by groups without decrypting
+// them and to decrypt the resulting table. The group-by keys remain in plain,
+// so a third party with only the public key can compute the encrypted SUM and
+// MEAN of every group, and the plain COUNT, using the homomorphic properties
+// of the cryptosystem. Encrypted cells are stored in the table as
+// "enc:<value>:<exp>", where value is the ciphertext and exp the plain
+// exponent of the number.Number. The MEAN results are stored as
+// "enc:<value>:<exp>/<count>", with the encrypted sum and the plain count of
+// the group, because the division can not be computed exactly over encrypted
+// values, so it is computed exactly when the table is decrypted.
+package csv
+
+import (
+	stdcsv "encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+	"github.com/lucasmenendez/gopaillier/pkg/stats"
+)
+
+// encryptedPrefix is the prefix of the encrypted cells of a Table.
+const encryptedPrefix = "enc:"
+
+// Supported aggregation functions.
+const (
+	Sum   = "sum"
+	Count = "count"
+	Mean  = "mean"
+)
+
+// Struct Table contains the header and the records of a CSV table. Every
+// record must have the same number of cells as the header.
+type Table struct {
+	Header  []string
+	Records [][]string
+}
+
+// Struct Aggregation contains the aggregation function (Sum, Count or Mean)
+// and the name of the column to aggregate.
+type Aggregation struct {
+	Func   string
+	Column string
+}
+
+// Function Read returns the Table read from the CSV content of the provided
+// io.Reader, using the first record as header. It returns an error if the
+// content is not a valid CSV or if it is empty.
+func Read(input io.Reader) (*Table, error) {
+	var records, err = stdcsv.NewReader(input).ReadAll()
+	if err != nil {
+		return nil, err
+	} else if len(records) == 0 {
+		return nil, errors.New("the CSV provided has no header")
+	}
+	return &Table{Header: records[0], Records: records[1:]}, nil
+}
+
+// Function Write writes the current Table as CSV content into the provided
+// io.Writer, including its header.
+func (table *Table) Write(output io.Writer) error {
+	var writer = stdcsv.NewWriter(output)
+	if err := writer.Write(table.Header); err != nil {
+		return err
+	} else if err := writer.WriteAll(table.Records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// Function Column returns the index of the column with the provided name into
+// the current Table. It returns an error if the column does not exist.
+func (table *Table) Column(name string) (int, error) {
+	for i, column := range table.Header {
+		if column == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("column '%s' not found", name)
+}
+
+// Function ParseAggregation returns the Aggregation defined by the provided
+// expression, with the format "func(column)", for example "mean(amount)". It
+// returns an error if the expression is malformed or if the function is not
+// supported.
+func ParseAggregation(expr string) (Aggregation, error) {
+	expr = strings.TrimSpace(expr)
+	var open = strings.Index(expr, "(")
+	if open <= 0 || !strings.HasSuffix(expr, ")") {
+		return Aggregation{}, fmt.Errorf("malformed aggregation '%s', expected func(column)", expr)
+	}
+
+	var agg = Aggregation{
+		Func:   strings.ToLower(expr[:open]),
+		Column: expr[open+1 : len(expr)-1],
+	}
+	if agg.Func != Sum && agg.Func != Count && agg.Func != Mean {
+		return Aggregation{}, fmt.Errorf("unsupported aggregation function '%s'", agg.Func)
+	} else if agg.Column == "" {
+		return Aggregation{}, fmt.Errorf("malformed aggregation '%s', empty column", expr)
+	}
+	return agg, nil
+}
+
+// Function String returns the name of the column that contains the results of
+// the current Aggregation, with the format "func(column)".
+func (agg Aggregation) String() string {
+	return fmt.Sprintf("%s(%s)", agg.Func, agg.Column)
+}
+
+// Function EncodeCell returns the content of a Table cell that contains the
+// provided encrypted number.Number.
+func EncodeCell(num *number.Number) string {
+	return fmt.Sprintf("%s%s:%s", encryptedPrefix, num.Value.String(), num.Exp.String())
+}
+
+// Function EncodeQuotient returns the content of a Table cell that contains
+// the provided stats.Quotient, with an encrypted numerator and a plain
+// denominator.
+func EncodeQuotient(quotient *stats.Quotient) string {
+	return EncodeCell(quotient.Numerator) + "/" + quotient.Denominator.String()
+}
+
+// Function DecodeQuotient returns the stats.Quotient stored into the provided
+// Table cell. If the cell contains an encrypted number.Number without a
+// denominator, the denominator of the result is 1. It returns an error if the
+// cell is not encrypted, if it is malformed or if the denominator is zero.
+func DecodeQuotient(cell string) (*stats.Quotient, error) {
+	var encrypted, rawDenominator, hasDenominator = strings.Cut(cell, "/")
+	var numerator, err = DecodeCell(encrypted)
+	if err != nil {
+		return nil, err
+	} else if !hasDenominator {
+		return &stats.Quotient{Numerator: numerator, Denominator: new(number.Number).SetInt(1)}, nil
+	}
+
+	var denominator *number.Number
+	if denominator, err = new(number.Number).SetString(rawDenominator); err != nil {
+		return nil, errors.New("malformed encrypted cell denominator")
+	} else if denominator.Value.Sign() == 0 {
+		return nil, errors.New("encrypted cell denominator must not be zero")
+	}
+	return &stats.Quotient{Numerator: numerator, Denominator: denominator}, nil
+}
+
+// Function DecodeCell returns the encrypted number.Number stored into the
+// provided Table cell. It returns an error if the cell is not encrypted, if it
+// is malformed or if its exponent is out of the range defined by
+// number.MaxExp.
+func DecodeCell(cell string) (*number.Number, error) {
+	if !IsEncrypted(cell) {
+		return nil, errors.New("the cell provided is not encrypted")
+	}
+
+	var parts = strings.Split(strings.TrimPrefix(cell, encryptedPrefix), ":")
+	if len(parts) != 2 {
+		return nil, errors.New("malformed encrypted cell")
+	}
+
+	var value, okValue = new(big.Int).SetString(parts[0], 10)
+	var exp, okExp = new(big.Int).SetString(parts[1], 10)
+	if !okValue || !okExp {
+		return nil, errors.New("malformed encrypted cell")
+	} else if exp.CmpAbs(big.NewInt(number.MaxExp)) > 0 {
+		return nil, errors.New("encrypted cell exponent out of range")
+	}
+	return new(number.Number).SetEncrypted(&number.Number{Value: value, Exp: exp}), nil
+}
+
+// Function IsEncrypted returns if the provided Table cell contains an
+// encrypted number.Number.
+func IsEncrypted(cell string) bool {
+	return strings.HasPrefix(cell, encryptedPrefix)
+}
+
+// Function EncryptColumns returns a copy of the provided Table with the cells
+// of the provided columns encrypted with the provided PublicKey. The cells of
+// the rest of columns are copied as they are. It returns an error if any
+// column does not exist, if any cell to encrypt is not a valid decimal number
+// or if the encryption fails.
+func EncryptColumns(pubKey sdk.PublicKey, table *Table, columns []string) (*Table, error) {
+	var indexes = make([]int, len(columns))
+	for i, column := range columns {
+		var err error
+		if indexes[i], err = table.Column(column); err != nil {
+			return nil, err
+		}
+	}
+
+	var client = sdk.NewClient(nil, pubKey)
+	var result = &Table{Header: append([]string{}, table.Header...)}
+	for r, record := range table.Records {
+		if len(record) != len(table.Header) {
+			return nil, fmt.Errorf("record %d: wrong number of cells", r+1)
+		}
+
+		var encrypted = append([]string{}, record...)
+		for _, index := range indexes {
+			var num, err = new(number.Number).SetString(strings.TrimSpace(record[index]))
+			if err != nil {
+				return nil, fmt.Errorf("record %d, column '%s': %w", r+1, table.Header[index], err)
+			} else if num, err = client.Encrypt(num); err != nil {
+				return nil, err
+			}
+			encrypted[index] = EncodeCell(num)
+		}
+		result.Records = append(result.Records, encrypted)
+	}
+	return result, nil
+}
+
+// Function Aggregate returns a new Table with the provided aggregations of the
+// current Table grouped by the plain values of the provided columns, in order
+// of first appearance. The result has a column per group-by column followed by
+// a column per aggregation, named as Aggregation.String() returns. The Sum and
+// Mean aggregations require encrypted columns and produce encrypted results
+// using the provided PublicKey, while the Count aggregation produces the plain
+// number of records of every group. It returns an error if any column does not
+// exist, if any group-by cell is encrypted or if any cell to sum or average is
+// not encrypted.
+func Aggregate(pubKey sdk.PublicKey, table *Table, groupBy []string, aggs []Aggregation) (*Table, error) {
+	if len(aggs) == 0 {
+		return nil, errors.New("no aggregations provided")
+	}
+
+	var keyIndexes = make([]int, len(groupBy))
+	var aggIndexes = make([]int, len(aggs))
+	var err error
+	for i, column := range groupBy {
+		if keyIndexes[i], err = table.Column(column); err != nil {
+			return nil, err
+		}
+	}
+	for i, agg := range aggs {
+		if aggIndexes[i], err = table.Column(agg.Column); err != nil {
+			return nil, err
+		}
+	}
+
+	// group the records by the values of the group-by columns keeping the
+	// order of first appearance
+	var keys [][]string
+	var groups = map[string][][]*number.Number{}
+	var counts = map[string]int{}
+	for r, record := range table.Records {
+		if len(record) != len(table.Header) {
+			return nil, fmt.Errorf("record %d: wrong number of cells", r+1)
+		}
+
+		var key = make([]string, len(keyIndexes))
+		for i, index := range keyIndexes {
+			if IsEncrypted(record[index]) {
+				return nil, fmt.Errorf("record %d: group-by column '%s' must be plain", r+1, table.Header[index])
+			}
+			key[i] = record[index]
+		}
+
+		var id = strings.Join(key, "\x00")
+		if _, exists := groups[id]; !exists {
+			keys = append(keys, key)
+			groups[id] = make([][]*number.Number, len(aggs))
+		}
+		counts[id]++
+
+		for i, agg := range aggs {
+			if agg.Func == Count {
+				continue
+			}
+
+			var value *number.Number
+			if value, err = DecodeCell(record[aggIndexes[i]]); err != nil {
+				return nil, fmt.Errorf("record %d, column '%s': %w", r+1, agg.Column, err)
+			}
+			groups[id][i] = append(groups[id][i], value)
+		}
+	}
+
+	var result = &Table{Header: append([]string{}, groupBy...)}
+	for _, agg := range aggs {
+		result.Header = append(result.Header, agg.String())
+	}
+
+	for _, key := range keys {
+		var id = strings.Join(key, "\x00")
+		var record = append([]string{}, key...)
+		for i, agg := range aggs {
+			var cell string
+			switch agg.Func {
+			case Count:
+				cell = strconv.Itoa(counts[id])
+			case Sum:
+				var sum *number.Number
+				if sum, _, err = stats.Sum(pubKey, groups[id][i]); err == nil {
+					cell = EncodeCell(sum)
+				}
+			case Mean:
+				var mean *stats.Quotient
+				if mean, _, err = stats.Mean(pubKey, groups[id][i]); err == nil {
+					cell = EncodeQuotient(mean)
+				}
+			default:
+				return nil, fmt.Errorf("unsupported aggregation function '%s'", agg.Func)
+			}
+			if err != nil {
+				return nil, err
+			}
+			record = append(record, cell)
+		}
+		result.Records = append(result.Records, record)
+	}
+	return result, nil
+}
+
+// Function DecryptTable returns a copy of the provided Table with all its
+// encrypted cells decrypted with the provided sdk.Client, as exact decimal
+// numbers. The cells with a denominator, such as the MEAN results, are
+// divided exactly after the decryption, rounding to stats.DefaultDecimals
+// decimals the results without a finite decimal representation. The plain
+// cells are copied as they are. It returns an error if any encrypted cell is
+// malformed or if the decryption fails.
+func DecryptTable(client *sdk.Client, table *Table) (*Table, error) {
+	var result = &Table{Header: append([]string{}, table.Header...)}
+	for r, record := range table.Records {
+		var decrypted = append([]string{}, record...)
+		for i, cell := range record {
+			if !IsEncrypted(cell) {
+				continue
+			}
+
+			var quotient, err = DecodeQuotient(cell)
+			if err != nil {
+				return nil, fmt.Errorf("record %d, column %d: %w", r+1, i+1, err)
+			}
+
+			var num *number.Number
+			if num, err = quotient.Decrypt(client, stats.DefaultDecimals); err != nil {
+				return nil, err
+			}
+			decrypted[i] = num.String()
+		}
+		result.Records = append(result.Records, decrypted)
+	}
+	return result, nil
+}
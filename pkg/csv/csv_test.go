@@ -0,0 +1,133 @@
+package csv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+var client, _ = sdk.InitClient(128)
+
+const input = `region,product,amount,units
+north,apple,10.5,3
+south,apple,4,1
+north,pear,-2.5,2
+north,apple,6,5
+`
+
+func TestReadWrite(t *testing.T) {
+	var table, err = Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if len(table.Header) != 4 || len(table.Records) != 4 {
+		t.Fatalf("expected 4 columns and 4 records, got %d and %d", len(table.Header), len(table.Records))
+	}
+
+	var output = new(bytes.Buffer)
+	if err = table.Write(output); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if output.String() != input {
+		t.Fatalf("expected %q, got %q", input, output.String())
+	}
+
+	if _, err = Read(strings.NewReader("")); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestParseAggregation(t *testing.T) {
+	var agg, err = ParseAggregation(" MEAN(amount)")
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if agg.Func != Mean || agg.Column != "amount" || agg.String() != "mean(amount)" {
+		t.Fatalf("unexpected aggregation %+v", agg)
+	}
+
+	for _, expr := range []string{"amount", "max(amount)", "sum()", "(amount)", "sum(amount"} {
+		if _, err = ParseAggregation(expr); err == nil {
+			t.Fatalf("%s: expected error, got nil", expr)
+		}
+	}
+}
+
+func TestEncryptAggregateDecrypt(t *testing.T) {
+	var table, _ = Read(strings.NewReader(input))
+	var encrypted, err = EncryptColumns(client.PubKey, table, []string{"amount", "units"})
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	for _, record := range encrypted.Records {
+		if IsEncrypted(record[0]) || !IsEncrypted(record[2]) || !IsEncrypted(record[3]) {
+			t.Fatalf("unexpected encrypted record %v", record)
+		}
+	}
+
+	var aggs = []Aggregation{{Sum, "amount"}, {Count, "amount"}, {Mean, "units"}}
+	var aggregated *Table
+	if aggregated, err = Aggregate(client.PubKey, encrypted, []string{"region"}, aggs); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var decrypted *Table
+	if decrypted, err = DecryptTable(client, aggregated); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var output = new(bytes.Buffer)
+	decrypted.Write(output)
+	var expected = `region,sum(amount),count(amount),mean(units)
+north,14,3,3.3333333333333333
+south,4,1,1
+`
+	if output.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, output.String())
+	}
+
+	// the mean is divided exactly after the decryption
+	table, _ = Read(strings.NewReader("key,value\na,1\na,1\na,1\nb,0.1\nb,0.2\n"))
+	encrypted, _ = EncryptColumns(client.PubKey, table, []string{"value"})
+	aggregated, _ = Aggregate(client.PubKey, encrypted, []string{"key"}, []Aggregation{{Mean, "value"}})
+	if decrypted, err = DecryptTable(client, aggregated); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if decrypted.Records[0][1] != "1" || decrypted.Records[1][1] != "0.15" {
+		t.Fatalf("expected 1 and 0.15, got %v", decrypted.Records)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var table, _ = Read(strings.NewReader(input))
+	if _, err := EncryptColumns(client.PubKey, table, []string{"unknown"}); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = EncryptColumns(client.PubKey, table, []string{"product"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// sum over a plain column
+	if _, err := Aggregate(client.PubKey, table, []string{"region"}, []Aggregation{{Sum, "amount"}}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// group by an encrypted column
+	var encrypted, _ = EncryptColumns(client.PubKey, table, []string{"amount"})
+	if _, err := Aggregate(client.PubKey, encrypted, []string{"amount"}, []Aggregation{{Count, "units"}}); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = Aggregate(client.PubKey, encrypted, []string{"region"}, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if _, err := DecodeCell("enc:12"); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = DecodeCell("12"); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = DecodeCell("enc:12:-1000000000"); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = DecodeQuotient("enc:12:0/0"); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = DecodeQuotient("enc:12:0/a"); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = DecodeQuotient("enc:12/3"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
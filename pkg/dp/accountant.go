@@ -0,0 +1,92 @@
+package dp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// tolerance absorbs the rounding errors of the floating point additions of the
+// spent budget.
+const tolerance = 1e-9
+
+// Struct Accountant keeps track of the privacy budget spent per key, using the
+// basic composition theorem: the epsilons and deltas of every noisy release
+// under the same key are added, and no more releases are allowed once they
+// exceed the Epsilon or the Delta budget. The key identifies the protected
+// dataset or the encryption key used. It is safe for concurrent use.
+type Accountant struct {
+	Epsilon float64
+	Delta   float64
+
+	mtx   sync.Mutex
+	spent map[string][2]float64
+}
+
+// Function NewAccountant returns a new Accountant with the provided epsilon
+// and delta budgets per key. It returns an error if the epsilon budget is not
+// positive or if the delta budget is negative.
+func NewAccountant(epsilon, delta float64) (*Accountant, error) {
+	if epsilon <= 0 {
+		return nil, errors.New("the epsilon budget must be positive")
+	} else if delta < 0 {
+		return nil, errors.New("the delta budget must not be negative")
+	}
+	return &Accountant{Epsilon: epsilon, Delta: delta, spent: map[string][2]float64{}}, nil
+}
+
+// Function Spend registers the use of the provided Mechanism under the
+// provided key. It returns an error if the Mechanism is not valid or if the
+// release would exceed the budget of the key, in which case nothing is spent.
+func (acc *Accountant) Spend(key string, mech *Mechanism) error {
+	if err := mech.Validate(); err != nil {
+		return err
+	}
+
+	var delta = 0.0
+	if mech.Distribution == Gaussian {
+		delta = mech.Delta
+	}
+
+	acc.mtx.Lock()
+	defer acc.mtx.Unlock()
+
+	var spent = acc.spent[key]
+	if spent[0]+mech.Epsilon > acc.Epsilon+tolerance || spent[1]+delta > acc.Delta+tolerance {
+		return fmt.Errorf("privacy budget exhausted for key '%s'", key)
+	}
+	acc.spent[key] = [2]float64{spent[0] + mech.Epsilon, spent[1] + delta}
+	return nil
+}
+
+// Function Remaining returns the epsilon and delta budgets that remain
+// available for the provided key.
+func (acc *Accountant) Remaining(key string) (float64, float64) {
+	acc.mtx.Lock()
+	defer acc.mtx.Unlock()
+
+	var spent = acc.spent[key]
+	return acc.Epsilon - spent[0], acc.Delta - spent[1]
+}
+
+// Function AddNoise returns the provided encrypted number.Number with the
+// noise of the provided Mechanism added homomorphically using the provided
+// sdk.PublicKey, spending the budget of the Mechanism under the provided key
+// only once the noise has been added. It returns an error if the noise can
+// not be added or if the budget of the key is exhausted.
+func (acc *Accountant) AddNoise(key string, pubKey sdk.PublicKey, encrypted *number.Number, mech *Mechanism) (*number.Number, error) {
+	if !encrypted.IsEncrypted() {
+		return nil, errors.New("the Number provided must be encrypted")
+	}
+
+	var noisy, err = AddNoise(pubKey, encrypted, mech)
+	if err != nil {
+		return nil, err
+	} else if err = acc.Spend(key, mech); err != nil {
+		return nil, err
+	}
+	return noisy, nil
+}
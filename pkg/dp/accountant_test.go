@@ -0,0 +1,68 @@
+package dp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+)
+
+func TestAccountant(t *testing.T) {
+	if _, err := NewAccountant(0, 0); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewAccountant(1, -1); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var acc, err = NewAccountant(1, 1e-5)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var laplace = &Mechanism{Distribution: Laplace, Epsilon: 0.1, Sensitivity: 1}
+	for i := 0; i < 10; i++ {
+		if err = acc.Spend("sales", laplace); err != nil {
+			t.Fatalf("%d: expected nil, got %s", i, err)
+		}
+	}
+	if err = acc.Spend("sales", laplace); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// other keys have their own budget
+	var gaussian = &Mechanism{Distribution: Gaussian, Epsilon: 0.5, Delta: 1e-5, Sensitivity: 1}
+	if err = acc.Spend("visits", gaussian); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if epsilon, delta := acc.Remaining("visits"); math.Abs(epsilon-0.5) > 1e-9 || delta != 0 {
+		t.Fatalf("expected 0.5 and 0, got %f and %f", epsilon, delta)
+	} else if err = acc.Spend("visits", gaussian); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// a failed spend does not consume budget
+	if epsilon, _ := acc.Remaining("visits"); math.Abs(epsilon-0.5) > 1e-9 {
+		t.Fatalf("expected 0.5, got %f", epsilon)
+	}
+}
+
+func TestAccountantAddNoise(t *testing.T) {
+	var acc, _ = NewAccountant(1, 0)
+	var mech = &Mechanism{Distribution: Laplace, Epsilon: 0.6, Sensitivity: 1, Precision: 1}
+	var encrypted, _ = client.Encrypt(new(number.Number).SetInt(10))
+
+	if _, err := acc.AddNoise("key", client.PubKey, encrypted, mech); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if _, err = acc.AddNoise("key", client.PubKey, encrypted, mech); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = acc.AddNoise("other", client.PubKey, new(number.Number).SetInt(10), mech); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// a failed noise addition does not consume budget
+	var invalid = &Mechanism{Distribution: Laplace, Epsilon: 0.6, Sensitivity: 1, Precision: -1}
+	if _, err := acc.AddNoise("other", client.PubKey, encrypted, invalid); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if epsilon, _ := acc.Remaining("other"); epsilon != 1 {
+		t.Fatalf("expected 1, got %f", epsilon)
+	}
+}
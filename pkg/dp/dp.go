@@ -0,0 +1,359 @@
+// Package dp allows to add differential privacy noise to encrypted
+// number.Number's before decrypting them, so the decrypted aggregates do not
+// leak the individual contributions, even over small groups. The noise is
+// sampled from a Laplace or a discrete Gaussian distribution calibrated with
+// the epsilon, delta and sensitivity of a Mechanism, and added
+// homomorphically with sdk.Add. Alternatively, every party could add an
+// encrypted noise share so no single party knows the total noise. An
+// Accountant keeps track of the privacy budget spent per key.
+//
+// The noise is sampled using crypto/rand as source of randomness directly as
+// an integer multiple of 10^-Precision from discrete distributions, instead of
+// rounding a continuous sample to a fixed number of decimals, which leaks the
+// original value through the gaps of the rounded floating point values
+// (Mironov, 2012). The encrypted result keeps a bounded exponent.
+package dp
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// Supported noise distributions.
+const (
+	Laplace  = "laplace"
+	Gaussian = "gaussian"
+)
+
+// Struct Mechanism contains the configuration of the noise to add: the
+// distribution (Laplace or Gaussian), the privacy parameters epsilon and
+// delta, the sensitivity of the query and the number of decimals of the
+// sampled noise. The discrete Laplace mechanism has scale sensitivity/epsilon and does
+// not use delta, while the discrete Gaussian mechanism has standard deviation
+// sensitivity * sqrt(2 * ln(1.25/delta)) / epsilon, a calibration that is only
+// valid for epsilon lower than 1.
+type Mechanism struct {
+	Distribution string
+	Epsilon      float64
+	Delta        float64
+	Sensitivity  float64
+	Precision    int
+}
+
+// maxScale is the maximum scale of the noise in units of 10^-Precision, so
+// the sampled noise fits in an int64 and the float64 arithmetic used to
+// sample it is exact on integers.
+const maxScale = 1 << 53
+
+// Function Validate returns an error if the current Mechanism has an
+// unsupported distribution or invalid parameters, or if its scale in units
+// of 10^-Precision exceeds 2^53.
+func (mech *Mechanism) Validate() error {
+	if mech.Distribution != Laplace && mech.Distribution != Gaussian {
+		return fmt.Errorf("unsupported distribution '%s'", mech.Distribution)
+	} else if mech.Epsilon <= 0 || math.IsInf(mech.Epsilon, 0) || math.IsNaN(mech.Epsilon) {
+		return errors.New("epsilon must be a positive number")
+	} else if mech.Sensitivity <= 0 || math.IsInf(mech.Sensitivity, 0) || math.IsNaN(mech.Sensitivity) {
+		return errors.New("sensitivity must be a positive number")
+	} else if mech.Distribution == Gaussian && (mech.Delta <= 0 || mech.Delta >= 1) {
+		return errors.New("delta must be between 0 and 1 for the gaussian distribution")
+	} else if mech.Distribution == Gaussian && mech.Epsilon >= 1 {
+		return errors.New("epsilon must be lower than 1 for the gaussian distribution")
+	} else if mech.Precision < 0 || mech.Precision > 15 {
+		return errors.New("precision must be between 0 and 15")
+	} else if mech.Scale()*math.Pow(10, float64(mech.Precision)) > maxScale {
+		return errors.New("the noise scale is too large for the precision")
+	}
+	return nil
+}
+
+// Function Scale returns the scale of the noise of the current Mechanism: the
+// scale of the Laplace distribution or the standard deviation of the discrete
+// Gaussian distribution.
+func (mech *Mechanism) Scale() float64 {
+	if mech.Distribution == Gaussian {
+		return mech.Sensitivity * math.Sqrt(2*math.Log(1.25/mech.Delta)) / mech.Epsilon
+	}
+	return mech.Sensitivity / mech.Epsilon
+}
+
+// Function Sample returns a plain number.Number with noise sampled from the
+// distribution of the current Mechanism, rounded to its Precision. It returns
+// an error if the Mechanism is not valid or if the sampling fails.
+func (mech *Mechanism) Sample() (*number.Number, error) {
+	return mech.sample(1)
+}
+
+// sample returns a plain number.Number with a noise share for the provided
+// number of parties, so the sum of the shares of all the parties follows the
+// distribution of the current Mechanism. The discrete Laplace distribution is
+// split as the difference of two Pólya(1/parties) variables, while the discrete
+// Gaussian distribution is split in shares with standard deviation
+// σ/sqrt(parties), whose sum approximates the target distribution.
+func (mech *Mechanism) sample(parties int) (*number.Number, error) {
+	if err := mech.Validate(); err != nil {
+		return nil, err
+	} else if parties < 1 {
+		return nil, errors.New("the number of parties must be positive")
+	}
+
+	var factor = math.Pow(10, float64(mech.Precision))
+	var scaled = mech.Scale() * factor
+	var noise int64
+	if mech.Distribution == Gaussian {
+		var value, err = discreteGaussian(scaled / math.Sqrt(float64(parties)))
+		if err != nil {
+			return nil, err
+		}
+		noise = value
+	} else if parties == 1 {
+		var value, err = discreteLaplace(scaled)
+		if err != nil {
+			return nil, err
+		}
+		noise = value
+	} else {
+		var shape = 1 / float64(parties)
+		var a, err = polya(shape, scaled)
+		if err != nil {
+			return nil, err
+		}
+
+		var b int64
+		if b, err = polya(shape, scaled); err != nil {
+			return nil, err
+		}
+		noise = a - b
+	}
+
+	var result = new(number.Number).SetInt(noise)
+	result.Exp = big.NewInt(int64(-mech.Precision))
+	return result, nil
+}
+
+// Function AddNoise returns the provided encrypted number.Number with noise
+// sampled from the provided Mechanism added homomorphically using the provided
+// sdk.PublicKey. It returns an error if the Mechanism is not valid, if the
+// number provided is not encrypted or if the sampling fails.
+func AddNoise(pubKey sdk.PublicKey, encrypted *number.Number, mech *Mechanism) (*number.Number, error) {
+	var noise, err = mech.Sample()
+	if err != nil {
+		return nil, err
+	}
+	return sdk.Add(pubKey, encrypted, noise)
+}
+
+// Function NoiseShare returns an encrypted noise share of the provided
+// Mechanism for one of the provided number of parties, encrypted with the
+// provided sdk.PublicKey. The addition of the shares of all the parties is
+// the noise of the Mechanism, without any party knowing it. It returns an
+// error if the Mechanism is not valid, if the number of parties is not
+// positive or if the sampling or the encryption fail.
+func NoiseShare(pubKey sdk.PublicKey, mech *Mechanism, parties int) (*number.Number, error) {
+	var share, err = mech.sample(parties)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.NewClient(nil, pubKey).Encrypt(share)
+}
+
+// Function AddShares returns the provided encrypted number.Number with the
+// provided encrypted noise shares added homomorphically using the provided
+// sdk.PublicKey. It returns an error if no shares are provided or if any
+// input is not encrypted.
+func AddShares(pubKey sdk.PublicKey, encrypted *number.Number, shares []*number.Number) (*number.Number, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no noise shares provided")
+	}
+
+	var result = encrypted
+	for _, share := range shares {
+		var err error
+		if result, err = sdk.AddEncrypted(pubKey, result, share); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// uniform returns a random float64 uniformly distributed in the open interval
+// (0, 1) using crypto/rand.
+func uniform() (float64, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return 0, err
+	}
+	var bits = binary.BigEndian.Uint64(buf[:]) >> 11
+	return (float64(bits) + 0.5) / (1 << 53), nil
+}
+
+// normal returns a random float64 from the standard normal distribution using
+// the Box-Muller transform.
+func normal() (float64, error) {
+	var u1, err = uniform()
+	if err != nil {
+		return 0, err
+	}
+
+	var u2 float64
+	if u2, err = uniform(); err != nil {
+		return 0, err
+	}
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2), nil
+}
+
+// gamma returns a random float64 from the Gamma distribution with the provided
+// shape and scale 1, using the Marsaglia-Tsang method, boosted with U^(1/shape)
+// for shapes lower than 1.
+func gamma(shape float64) (float64, error) {
+	if shape < 1 {
+		var g, err = gamma(shape + 1)
+		if err != nil {
+			return 0, err
+		}
+
+		var u float64
+		if u, err = uniform(); err != nil {
+			return 0, err
+		}
+		return g * math.Pow(u, 1/shape), nil
+	}
+
+	var d = shape - 1.0/3
+	var c = 1 / math.Sqrt(9*d)
+	for {
+		var x, err = normal()
+		if err != nil {
+			return 0, err
+		}
+
+		var v = 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+
+		var u float64
+		if u, err = uniform(); err != nil {
+			return 0, err
+		} else if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v, nil
+		}
+	}
+}
+
+// poisson returns a random int64 from the Poisson distribution with the
+// provided mean, multiplying uniforms for small means and using the
+// transformed rejection with squeeze method of Hörmann (PTRS) otherwise.
+func poisson(mean float64) (int64, error) {
+	if mean < 10 {
+		var limit, product = math.Exp(-mean), 1.0
+		for k := int64(0); ; k++ {
+			var u, err = uniform()
+			if err != nil {
+				return 0, err
+			}
+			if product *= u; product <= limit {
+				return k, nil
+			}
+		}
+	}
+
+	var slam, loglam = math.Sqrt(mean), math.Log(mean)
+	var b = 0.931 + 2.53*slam
+	var a = -0.059 + 0.02483*b
+	var invalpha = 1.1239 + 1.1328/(b-3.4)
+	var vr = 0.9277 - 3.6224/(b-2)
+	for {
+		var u, err = uniform()
+		if err != nil {
+			return 0, err
+		}
+
+		var v float64
+		if v, err = uniform(); err != nil {
+			return 0, err
+		}
+
+		u -= 0.5
+		var us = 0.5 - math.Abs(u)
+		var k = math.Floor((2*a/us+b)*u + mean + 0.43)
+		if us >= 0.07 && v <= vr {
+			return int64(k), nil
+		} else if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+
+		var lgam, _ = math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invalpha)-math.Log(a/(us*us)+b) <= -mean+k*loglam-lgam {
+			return int64(k), nil
+		}
+	}
+}
+
+// polya returns a random int64 from the Pólya (negative binomial)
+// distribution with the provided shape and success probability
+// exp(-1/scale), as a Poisson variable whose mean is a Gamma variable. The
+// sum of n variables with shape 1/n is a geometric variable, and the
+// difference of two geometric variables is a discrete Laplace variable with
+// the provided scale.
+func polya(shape, scale float64) (int64, error) {
+	var g, err = gamma(shape)
+	if err != nil {
+		return 0, err
+	}
+	return poisson(g / math.Expm1(1/scale))
+}
+
+// discreteLaplace returns a random int64 from the discrete Laplace
+// distribution with the provided scale, P(x) ∝ exp(-|x|/scale), sampling a
+// geometric magnitude and a random sign, and rejecting the negative zero.
+func discreteLaplace(scale float64) (int64, error) {
+	for {
+		var u, err = uniform()
+		if err != nil {
+			return 0, err
+		}
+		var magnitude = int64(math.Floor(-scale * math.Log(u)))
+
+		var sign float64
+		if sign, err = uniform(); err != nil {
+			return 0, err
+		} else if sign < 0.5 {
+			if magnitude == 0 {
+				continue
+			}
+			return -magnitude, nil
+		}
+		return magnitude, nil
+	}
+}
+
+// discreteGaussian returns a random int64 from the discrete Gaussian
+// distribution with the provided standard deviation, using the rejection
+// sampling from the discrete Laplace distribution of Canonne, Kamath and
+// Steinke.
+func discreteGaussian(sigma float64) (int64, error) {
+	var t = math.Floor(sigma) + 1
+	for {
+		var y, err = discreteLaplace(t)
+		if err != nil {
+			return 0, err
+		}
+
+		var diff = math.Abs(float64(y)) - sigma*sigma/t
+		var u float64
+		if u, err = uniform(); err != nil {
+			return 0, err
+		} else if u < math.Exp(-diff*diff/(2*sigma*sigma)) {
+			return y, nil
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package dp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+var client, _ = sdk.InitClient(128)
+
+// moments returns the mean and the variance of the provided number of samples
+// of the provided Mechanism.
+func moments(t *testing.T, mech *Mechanism, parties, samples int) (float64, float64) {
+	var sum, sumSq = 0.0, 0.0
+	for i := 0; i < samples; i++ {
+		var total = 0.0
+		for p := 0; p < parties; p++ {
+			var noise, err = mech.sample(parties)
+			if err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+			total += noise.Float()
+		}
+		sum += total
+		sumSq += total * total
+	}
+
+	var mean = sum / float64(samples)
+	return mean, sumSq/float64(samples) - mean*mean
+}
+
+func TestValidate(t *testing.T) {
+	var tests = []Mechanism{
+		{Distribution: "uniform", Epsilon: 1, Sensitivity: 1},
+		{Distribution: Laplace, Epsilon: 0, Sensitivity: 1},
+		{Distribution: Laplace, Epsilon: 1, Sensitivity: -1},
+		{Distribution: Laplace, Epsilon: math.Inf(1), Sensitivity: 1},
+		{Distribution: Gaussian, Epsilon: 0.5, Sensitivity: 1},
+		{Distribution: Gaussian, Epsilon: 0.5, Sensitivity: 1, Delta: 1},
+		{Distribution: Gaussian, Epsilon: 1, Sensitivity: 1, Delta: 1e-5},
+		{Distribution: Laplace, Epsilon: 1, Sensitivity: 1, Precision: -1},
+		{Distribution: Laplace, Epsilon: 1e-9, Sensitivity: 1, Precision: 15},
+	}
+	for _, mech := range tests {
+		if err := mech.Validate(); err == nil {
+			t.Fatalf("%+v: expected error, got nil", mech)
+		} else if _, err = mech.Sample(); err == nil {
+			t.Fatalf("%+v: expected error, got nil", mech)
+		}
+	}
+
+	var mech = Mechanism{Distribution: Gaussian, Epsilon: 0.5, Delta: 1e-5, Sensitivity: 2}
+	if err := mech.Validate(); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if expected := 2 * math.Sqrt(2*math.Log(1.25/1e-5)) / 0.5; mech.Scale() != expected {
+		t.Fatalf("expected %f, got %f", expected, mech.Scale())
+	}
+}
+
+func TestLaplace(t *testing.T) {
+	// scale b = 2, variance 2b^2 = 8
+	var mech = &Mechanism{Distribution: Laplace, Epsilon: 0.5, Sensitivity: 1, Precision: 3}
+	for _, parties := range []int{1, 4} {
+		var mean, variance = moments(t, mech, parties, 4000)
+		if math.Abs(mean) > 0.3 || math.Abs(variance-8) > 1.5 {
+			t.Fatalf("%d parties: unexpected mean %f and variance %f", parties, mean, variance)
+		}
+	}
+}
+
+func TestPoisson(t *testing.T) {
+	for _, mean := range []float64{0.5, 4, 30, 1e6} {
+		var sum, sumSq = 0.0, 0.0
+		for i := 0; i < 4000; i++ {
+			var k, err = poisson(mean)
+			if err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+			sum += float64(k)
+			sumSq += float64(k) * float64(k)
+		}
+
+		var sampleMean = sum / 4000
+		var variance = sumSq/4000 - sampleMean*sampleMean
+		if math.Abs(sampleMean-mean) > 0.1*mean || math.Abs(variance-mean) > 0.2*mean {
+			t.Fatalf("mean %f: unexpected mean %f and variance %f", mean, sampleMean, variance)
+		}
+	}
+}
+
+func TestGaussian(t *testing.T) {
+	var mech = &Mechanism{Distribution: Gaussian, Epsilon: 0.9, Delta: 1e-3, Sensitivity: 1, Precision: 0}
+	var sigma = mech.Scale()
+	for _, parties := range []int{1, 3} {
+		var mean, variance = moments(t, mech, parties, 4000)
+		if math.Abs(mean) > 0.5 || math.Abs(variance-sigma*sigma)/(sigma*sigma) > 0.15 {
+			t.Fatalf("%d parties: unexpected mean %f and variance %f, expected %f", parties, mean, variance, sigma*sigma)
+		}
+	}
+
+	var noise, _ = mech.Sample()
+	if noise.Exp.Int64() != 0 || noise.Float() != math.Trunc(noise.Float()) {
+		t.Fatalf("expected integer noise, got %s", noise)
+	}
+}
+
+func TestAddNoise(t *testing.T) {
+	var value, _ = new(number.Number).SetFloat(1000.5)
+	var encrypted, _ = client.Encrypt(value)
+	var mech = &Mechanism{Distribution: Laplace, Epsilon: 1, Sensitivity: 1, Precision: 2}
+
+	var noisy, err = AddNoise(client.PubKey, encrypted, mech)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var decrypted, _ = client.Decrypt(noisy)
+	if diff := math.Abs(decrypted.Float() - 1000.5); diff > 50 {
+		t.Fatalf("unexpected noisy value %f", decrypted.Float())
+	}
+
+	if _, err = AddNoise(client.PubKey, value, mech); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestNoiseShares(t *testing.T) {
+	var value = new(number.Number).SetInt(500)
+	var encrypted, _ = client.Encrypt(value)
+	var mech = &Mechanism{Distribution: Gaussian, Epsilon: 0.9, Delta: 1e-5, Sensitivity: 1, Precision: 1}
+
+	var shares = make([]*number.Number, 5)
+	for i := range shares {
+		var err error
+		if shares[i], err = NoiseShare(client.PubKey, mech, len(shares)); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	}
+
+	var noisy, err = AddShares(client.PubKey, encrypted, shares)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var decrypted, _ = client.Decrypt(noisy)
+	if diff := math.Abs(decrypted.Float() - 500); diff > 10*mech.Scale() {
+		t.Fatalf("unexpected noisy value %f", decrypted.Float())
+	}
+
+	if _, err = AddShares(client.PubKey, encrypted, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NoiseShare(client.PubKey, mech, 0); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,306 @@
+// Package elgamal is the Go implementation of the additively homomorphic
+// variant of the ElGamal cryptosystem over elliptic curves, also known as
+// exponential ElGamal or EC-ElGamal. The plaintext m is encoded as the curve
+// point m·G, so the addition of two ciphertexts results in the encryption of
+// the addition of both plaintexts. Decryption requires to solve a bounded
+// discrete logarithm, which is done using a precomputed baby-step giant-step
+// table, so it is suitable for small-range values (votes, counters, booleans).
+// Its ciphertexts are much smaller than Paillier ones with equivalent
+// security, and they are encoded as big.Int's to be used with the sdk package.
+// Read more: https://en.wikipedia.org/wiki/ElGamal_encryption
+package elgamal
+
+import (
+	"bytes"
+	"context"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// pointSize is the length in bytes of a compressed point of the curve used.
+// The point at infinity is encoded as pointSize zero bytes.
+const pointSize = 33
+
+// MaxBound is the maximum bound of the plaintexts accepted by NewKeys. The
+// baby-step table has ⌈sqrt(2·bound + 1)⌉ points, so it limits the table to
+// less than 2^19 points, which requires tens of MiB of memory, and the
+// decryption to the same number of giant steps.
+const MaxBound = 1 << 36
+
+// Struct PublicKey includes the curve used, which is P-256, and the public
+// point Y = x·G of the key, with the bound of the plaintexts that can be
+// decrypted.
+type PublicKey struct {
+	Curve elliptic.Curve
+	X, Y  *big.Int
+	Bound int64
+}
+
+// Struct PrivateKey includes the required secret scalar x (d), the associated
+// PublicKey and the baby-step table used to compute the discrete logarithms
+// during the decryption.
+type PrivateKey struct {
+	d      *big.Int
+	table  map[string]int64
+	step   int64
+	PubKey *PublicKey
+}
+
+// Function NewKeys generates a new random elgamal.PrivateKey, including its
+// elgamal.PublicKey, over the P-256 curve. The bound provided defines the
+// range of plaintexts [-bound, bound] that can be encrypted and decrypted with
+// the key, and it is used to precompute the baby-step table with
+// sqrt(2·bound + 1) points. It returns an error if the bound is not positive,
+// if it is greater than MaxBound or if the random number generation fails.
+func NewKeys(bound int64) (*PrivateKey, error) {
+	return NewKeysContext(context.Background(), bound)
+}
+
+// Function NewKeysContext works as NewKeys but checks the provided context
+// during the precomputation of the baby-steps table, returning ctx.Err() if it
+// is cancelled before the key generation finishes.
+func NewKeysContext(ctx context.Context, bound int64) (*PrivateKey, error) {
+	if bound < 1 || bound > MaxBound {
+		return nil, fmt.Errorf("bound must be positive and not greater than %d", int64(MaxBound))
+	}
+
+	// Compute the private scalar x (d) and the public point Y = x·G.
+	var curve = elliptic.P256()
+	var d, err = randScalar(curve)
+	if err != nil {
+		return nil, err
+	}
+	var x, y = curve.ScalarBaseMult(d.FillBytes(make([]byte, 32)))
+
+	// Precompute the baby-steps j·G for j in [0, step), where:
+	//		step = ⌈sqrt(2·bound + 1)⌉
+	var step = int64(math.Ceil(math.Sqrt(float64(2*bound + 1))))
+	var table = make(map[string]int64, step)
+	var px, py = new(big.Int), new(big.Int)
+	for j := int64(0); j < step; j++ {
+		if j%4096 == 0 {
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		table[string(encodePoint(curve, px, py))] = j
+		px, py = curve.Add(px, py, curve.Params().Gx, curve.Params().Gy)
+	}
+
+	return &PrivateKey{
+		d, table, step,
+		&PublicKey{curve, x, y, bound},
+	}, nil
+}
+
+// Function PlaintextBits returns the number of bits of the non-negative
+// plaintexts that can be encrypted and decrypted with the current
+// elgamal.PublicKey, which are limited by its bound.
+func (key *PublicKey) PlaintextBits() int {
+	return big.NewInt(key.Bound).BitLen() - 1
+}
+
+// Function Encrypt convert the received input big.Int into its encrypted
+// version using the current elgamal.PublicKey. Returns an error if the
+// provided input is out of the [-bound, bound] range of the key or if the
+// random number generation fails.
+func (key *PublicKey) Encrypt(input *big.Int) (*big.Int, error) {
+	if new(big.Int).Abs(input).Cmp(big.NewInt(key.Bound)) > 0 {
+		return nil, errors.New("input out of bounds on encrypt")
+	}
+
+	var r, err = randScalar(key.Curve)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute encrypted message (C1, C2) of input (m), where:
+	//		C1 = r·G
+	//		C2 = m·G + r·Y
+	var c1x, c1y = key.Curve.ScalarBaseMult(r.FillBytes(make([]byte, 32)))
+	var mx, my = key.scalarBaseMult(input)
+	var rx, ry = key.Curve.ScalarMult(key.X, key.Y, r.FillBytes(make([]byte, 32)))
+	var c2x, c2y = key.Curve.Add(mx, my, rx, ry)
+	return key.encode(c1x, c1y, c2x, c2y), nil
+}
+
+// Function Decrypt convert the received encrypted input big.Int into its
+// decrypted version using the current elgamal.PrivateKey. Returns an error if
+// the provided input is not a valid ciphertext or if the decrypted value is
+// out of the [-bound, bound] range of the key.
+func (key *PrivateKey) Decrypt(input *big.Int) (*big.Int, error) {
+	var curve = key.PubKey.Curve
+	var c1x, c1y, c2x, c2y, err = key.PubKey.decode(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute the encoded message M = m·G, where:
+	//		M = C2 - x·C1
+	var sx, sy = curve.ScalarMult(c1x, c1y, key.d.FillBytes(make([]byte, 32)))
+	sx, sy = negPoint(curve, sx, sy)
+	var mx, my = curve.Add(c2x, c2y, sx, sy)
+
+	// Shift the message to the range [0, 2·bound] and solve the discrete
+	// logarithm with the baby-step giant-step algorithm, where:
+	//		M + bound·G = (i·step + j)·G => m = i·step + j - bound
+	var bx, by = key.PubKey.scalarBaseMult(big.NewInt(key.PubKey.Bound))
+	mx, my = curve.Add(mx, my, bx, by)
+
+	var gx, gy = key.PubKey.scalarBaseMult(big.NewInt(-key.step))
+	for i := int64(0); i <= key.step; i++ {
+		if j, ok := key.table[string(encodePoint(curve, mx, my))]; ok {
+			var m = i*key.step + j - key.PubKey.Bound
+			if m <= key.PubKey.Bound {
+				return big.NewInt(m), nil
+			}
+			break
+		}
+		mx, my = curve.Add(mx, my, gx, gy)
+	}
+
+	return nil, errors.New("decrypted value out of bounds")
+}
+
+// Function AddEncrypted returns the result of adding both encrypted big.Int's
+// provided as input (a and b). Both inputs must be valid ciphertexts generated
+// with the current elgamal.PublicKey, otherwise it returns nil.
+func (key *PublicKey) AddEncrypted(a, b *big.Int) *big.Int {
+	// Compute a + b, where:
+	//		a = (A1, A2) = E(m1) & b = (B1, B2) = E(m2)
+	//		a + b = (A1 + B1, A2 + B2)
+	var a1x, a1y, a2x, a2y, err = key.decode(a)
+	if err != nil {
+		return nil
+	}
+	var b1x, b1y, b2x, b2y *big.Int
+	if b1x, b1y, b2x, b2y, err = key.decode(b); err != nil {
+		return nil
+	}
+	var c1x, c1y = key.Curve.Add(a1x, a1y, b1x, b1y)
+	var c2x, c2y = key.Curve.Add(a2x, a2y, b2x, b2y)
+	return key.encode(c1x, c1y, c2x, c2y)
+}
+
+// Function Add returns the result of adding the the encrypted big.Int
+// provided as a input to the plain big.Int provided as b input. The encrypted
+// input must be a valid ciphertext generated with the current
+// elgamal.PublicKey, otherwise it returns nil.
+func (key *PublicKey) Add(a, b *big.Int) *big.Int {
+	// Compute a + b, where:
+	//		a = (A1, A2) = E(m1) & b = m2
+	//		a + b = (A1, A2 + m2·G)
+	var a1x, a1y, a2x, a2y, err = key.decode(a)
+	if err != nil {
+		return nil
+	}
+	var bx, by = key.scalarBaseMult(b)
+	var c2x, c2y = key.Curve.Add(a2x, a2y, bx, by)
+	return key.encode(a1x, a1y, c2x, c2y)
+}
+
+// Function Mul returns the result of to multiplying the the encrypted big.Int
+// provided as a input to the plain big.Int provided as b input. The encrypted
+// input must be a valid ciphertext generated with the current
+// elgamal.PublicKey, otherwise it returns nil.
+func (key *PublicKey) Mul(a, b *big.Int) *big.Int {
+	// Compute a * b, where:
+	//		a = (A1, A2) = E(m1) & b = m2
+	//		a * b = (m2·A1, m2·A2)
+	var a1x, a1y, a2x, a2y, err = key.decode(a)
+	if err != nil {
+		return nil
+	}
+	var scalar = key.scalar(b)
+	var c1x, c1y = key.Curve.ScalarMult(a1x, a1y, scalar)
+	var c2x, c2y = key.Curve.ScalarMult(a2x, a2y, scalar)
+	return key.encode(c1x, c1y, c2x, c2y)
+}
+
+// scalar returns the provided big.Int reduced modulo the order of the curve,
+// mapping negative values into the field, as a fixed size byte slice.
+func (key *PublicKey) scalar(input *big.Int) []byte {
+	var n = key.Curve.Params().N
+	var reduced = new(big.Int).Mod(input, n)
+	return reduced.FillBytes(make([]byte, (n.BitLen()+7)/8))
+}
+
+// scalarBaseMult returns the point m·G for the provided big.Int m, which could
+// be negative.
+func (key *PublicKey) scalarBaseMult(input *big.Int) (*big.Int, *big.Int) {
+	return key.Curve.ScalarBaseMult(key.scalar(input))
+}
+
+// encode returns the big.Int representation of the ciphertext (C1, C2)
+// concatenating the compressed version of both points.
+func (key *PublicKey) encode(c1x, c1y, c2x, c2y *big.Int) *big.Int {
+	var raw = append(encodePoint(key.Curve, c1x, c1y), encodePoint(key.Curve, c2x, c2y)...)
+	return new(big.Int).SetBytes(raw)
+}
+
+// decode returns the points (C1, C2) of the ciphertext encoded into the
+// provided big.Int. It returns an error if the input is nil or if it does not
+// contain two valid points of the curve.
+func (key *PublicKey) decode(input *big.Int) (c1x, c1y, c2x, c2y *big.Int, err error) {
+	if input == nil || input.Sign() < 0 || input.BitLen() > 2*pointSize*8 {
+		err = errors.New("invalid ciphertext length")
+		return
+	}
+
+	var raw = input.FillBytes(make([]byte, 2*pointSize))
+	if c1x, c1y, err = decodePoint(key.Curve, raw[:pointSize]); err != nil {
+		return
+	}
+	c2x, c2y, err = decodePoint(key.Curve, raw[pointSize:])
+	return
+}
+
+// negPoint returns the negated version of the provided point, where:
+//
+//	-(x, y) = (x, p - y)
+func negPoint(curve elliptic.Curve, x, y *big.Int) (*big.Int, *big.Int) {
+	if y.Sign() == 0 {
+		return x, y
+	}
+	return x, new(big.Int).Sub(curve.Params().P, y)
+}
+
+// encodePoint returns the compressed version of the provided point, encoding
+// the point at infinity (0, 0) as zero bytes.
+func encodePoint(curve elliptic.Curve, x, y *big.Int) []byte {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return make([]byte, pointSize)
+	}
+	return elliptic.MarshalCompressed(curve, x, y)
+}
+
+// decodePoint returns the point encoded in the provided compressed point bytes.
+// It returns an error if it is not a valid point of the curve.
+func decodePoint(curve elliptic.Curve, raw []byte) (*big.Int, *big.Int, error) {
+	if bytes.Equal(raw, make([]byte, pointSize)) {
+		return new(big.Int), new(big.Int), nil
+	}
+
+	var x, y = elliptic.UnmarshalCompressed(curve, raw)
+	if x == nil {
+		return nil, nil, errors.New("invalid ciphertext point")
+	}
+	return x, y, nil
+}
+
+// randScalar returns a random non-zero scalar lower than the order of the
+// curve provided.
+func randScalar(curve elliptic.Curve) (*big.Int, error) {
+	for {
+		var r, err = rand.Int(rand.Reader, curve.Params().N)
+		if err != nil {
+			return nil, err
+		} else if r.Sign() != 0 {
+			return r, nil
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package elgamal
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/internal/conformance"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// Ensure that the keys satisfy the sdk interfaces.
+var _ sdk.PublicKey = (*PublicKey)(nil)
+var _ sdk.PrivateKey = (*PrivateKey)(nil)
+
+var key, _ = NewKeys(10000)
+
+func TestNewKeys(t *testing.T) {
+	if _, err := NewKeys(100); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if _, err = NewKeys(0); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewKeys(-10); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewKeys(MaxBound + 1); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestNewKeysContext(t *testing.T) {
+	if _, err := NewKeysContext(context.Background(), 100); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewKeysContext(ctx, MaxBound); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, input := range []int64{0, 1, 12, -12, 10000, -10000, 9999, 4321} {
+		var inputA = big.NewInt(input)
+		var encryptedA, err = key.PubKey.Encrypt(inputA)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		var decryptedA *big.Int
+		if decryptedA, err = key.Decrypt(encryptedA); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if inputA.Cmp(decryptedA) != 0 {
+			t.Fatalf("expected %d, got %d", inputA, decryptedA)
+		}
+	}
+
+	if _, err := key.PubKey.Encrypt(big.NewInt(10001)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = key.PubKey.Encrypt(big.NewInt(-10001)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// out of bounds results and invalid ciphertexts
+	var encrypted, _ = key.PubKey.Encrypt(big.NewInt(10000))
+	if _, err := key.Decrypt(key.PubKey.Add(encrypted, big.NewInt(1))); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = key.Decrypt(big.NewInt(12345)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = key.Decrypt(new(big.Int).Lsh(encrypted, 8)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestAddEncrypt(t *testing.T) {
+	var inputA = new(big.Int).SetInt64(12)
+	var inputB = new(big.Int).SetInt64(-30)
+	var expectedRes1 = new(big.Int).SetInt64(-18)
+	var expectedRes2 = new(big.Int).SetInt64(-48)
+
+	var encryptedA, _ = key.PubKey.Encrypt(inputA)
+	var encryptedB, _ = key.PubKey.Encrypt(inputB)
+	var encryptedSum = key.PubKey.AddEncrypted(encryptedA, encryptedB)
+
+	var result, _ = key.Decrypt(encryptedSum)
+	if result.Cmp(expectedRes1) != 0 {
+		t.Fatalf("expected %d, got %d", expectedRes1, result)
+	}
+
+	encryptedSum = key.PubKey.AddEncrypted(encryptedSum, encryptedB)
+	result, _ = key.Decrypt(encryptedSum)
+	if result.Cmp(expectedRes2) != 0 {
+		t.Fatalf("expected %d, got %d", expectedRes2, result)
+	}
+
+	// adding the opposite results in zero
+	var encryptedNegA, _ = key.PubKey.Encrypt(new(big.Int).Neg(inputA))
+	encryptedSum = key.PubKey.AddEncrypted(encryptedA, encryptedNegA)
+	if result, _ = key.Decrypt(encryptedSum); result.Sign() != 0 {
+		t.Fatalf("expected 0, got %d", result)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	var inputA = new(big.Int).SetInt64(12)
+	var inputB = new(big.Int).SetInt64(3)
+	var expectedRes1 = new(big.Int).SetInt64(15)
+	var expectedRes2 = new(big.Int).SetInt64(18)
+
+	var encryptedA, _ = key.PubKey.Encrypt(inputA)
+	var encryptedSum = key.PubKey.Add(encryptedA, inputB)
+
+	var result, _ = key.Decrypt(encryptedSum)
+	if result.Cmp(expectedRes1) != 0 {
+		t.Fatalf("expected %d, got %d", expectedRes1, result)
+	}
+
+	encryptedSum = key.PubKey.Add(encryptedSum, inputB)
+	result, _ = key.Decrypt(encryptedSum)
+	if result.Cmp(expectedRes2) != 0 {
+		t.Fatalf("expected %d, got %d", expectedRes2, result)
+	}
+}
+
+func TestMul(t *testing.T) {
+	var inputA = new(big.Int).SetInt64(12)
+	var inputB = new(big.Int).SetInt64(-3)
+	var expectedRes1 = new(big.Int).SetInt64(-36)
+	var expectedRes2 = new(big.Int).SetInt64(108)
+
+	var encryptedA, _ = key.PubKey.Encrypt(inputA)
+	var encryptedMul = key.PubKey.Mul(encryptedA, inputB)
+
+	var result, _ = key.Decrypt(encryptedMul)
+	if result.Cmp(expectedRes1) != 0 {
+		t.Fatalf("expected %d, got %d", expectedRes1, result)
+	}
+
+	encryptedMul = key.PubKey.Mul(encryptedMul, inputB)
+	result, _ = key.Decrypt(encryptedMul)
+	if result.Cmp(expectedRes2) != 0 {
+		t.Fatalf("expected %d, got %d", expectedRes2, result)
+	}
+
+	// multiplying by zero results in the point at infinity
+	encryptedMul = key.PubKey.Mul(encryptedA, big.NewInt(0))
+	if result, _ = key.Decrypt(encryptedMul); result.Sign() != 0 {
+		t.Fatalf("expected 0, got %d", result)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	var encrypted, _ = key.PubKey.Encrypt(big.NewInt(12))
+	for _, malformed := range []*big.Int{nil, big.NewInt(12345), big.NewInt(-1), new(big.Int).Lsh(encrypted, 8)} {
+		if result := key.PubKey.AddEncrypted(encrypted, malformed); result != nil {
+			t.Fatalf("expected nil, got %d", result)
+		} else if result = key.PubKey.AddEncrypted(malformed, encrypted); result != nil {
+			t.Fatalf("expected nil, got %d", result)
+		} else if result = key.PubKey.Add(malformed, big.NewInt(1)); result != nil {
+			t.Fatalf("expected nil, got %d", result)
+		} else if result = key.PubKey.Mul(malformed, big.NewInt(2)); result != nil {
+			t.Fatalf("expected nil, got %d", result)
+		} else if _, err := key.Decrypt(malformed); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	}
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, key, key.PubKey, big.NewInt(key.PubKey.Bound))
+}
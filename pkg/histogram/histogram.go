@@ -0,0 +1,178 @@
+// Package histogram allows to compute histograms over private values without
+// revealing them. Each client encodes its value as a one-hot vector of
+// encrypted buckets, E(1) for the bucket that contains the value and E(0) for
+// the rest, optionally with zero-knowledge proofs that every bucket encrypts a
+// bit and that exactly one of them encrypts 1. The server adds the vectors
+// bucket by bucket using the homomorphic addition of Paillier, obtaining the
+// encrypted count of every bucket, that only the keyholder can decrypt.
+package histogram
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+var bits = []*big.Int{big.NewInt(0), big.NewInt(1)}
+var one = []*big.Int{big.NewInt(1)}
+
+// Struct Histogram contains the paillier.PublicKey used to encrypt the entries,
+// the sorted boundaries of the buckets and the encrypted counts of every
+// bucket. The boundaries b_0 < b_1 < ... < b_k define k + 2 buckets:
+// (-∞, b_0), [b_0, b_1), ..., [b_k, +∞). If RequireProofs is true, only the
+// entries with valid proofs are accepted.
+type Histogram struct {
+	PubKey        *paillier.PublicKey
+	Boundaries    []float64
+	RequireProofs bool
+	Counts        []*big.Int
+	Entries       int
+}
+
+// Struct Entry contains the one-hot encrypted buckets of a value and,
+// optionally, the proofs that every bucket encrypts a bit and that the sum of
+// all of them is 1.
+type Entry struct {
+	Buckets  []*big.Int
+	Proofs   []*paillier.MembershipProof
+	SumProof *paillier.MembershipProof
+}
+
+// Function New returns a new empty Histogram with the provided
+// paillier.PublicKey and bucket boundaries, initializing every bucket count to
+// an encrypted zero. It returns an error if the boundaries are not sorted
+// without duplicates or if the encryption fails.
+func New(pubKey *paillier.PublicKey, boundaries []float64) (*Histogram, error) {
+	for i := 1; i < len(boundaries); i++ {
+		if boundaries[i-1] >= boundaries[i] {
+			return nil, errors.New("boundaries must be sorted in ascending order without duplicates")
+		}
+	}
+
+	var histogram = &Histogram{
+		PubKey:     pubKey,
+		Boundaries: boundaries,
+		Counts:     make([]*big.Int, len(boundaries)+1),
+	}
+
+	var err error
+	for i := range histogram.Counts {
+		if histogram.Counts[i], err = pubKey.Encrypt(big.NewInt(0)); err != nil {
+			return nil, err
+		}
+	}
+	return histogram, nil
+}
+
+// Function Bucket returns the index of the bucket that contains the value
+// provided.
+func (histogram *Histogram) Bucket(value float64) int {
+	return sort.Search(len(histogram.Boundaries), func(i int) bool {
+		return histogram.Boundaries[i] > value
+	})
+}
+
+// Function Encode returns the Entry that encodes the value provided as a
+// one-hot vector of encrypted buckets. If prove is true, it includes the
+// proofs that every bucket encrypts a bit and that their sum is 1. It is
+// intended to be used by the clients. It returns an error if the encryption
+// or the proofs generation fails.
+func (histogram *Histogram) Encode(value float64, prove bool) (*Entry, error) {
+	var key = histogram.PubKey
+	var bucket = histogram.Bucket(value)
+	var entry = &Entry{Buckets: make([]*big.Int, len(histogram.Counts))}
+	var inputs = make([]*big.Int, len(histogram.Counts))
+	var nonces = make([]*big.Int, len(histogram.Counts))
+
+	var err error
+	for i := range entry.Buckets {
+		if inputs[i] = bits[0]; i == bucket {
+			inputs[i] = bits[1]
+		}
+
+		if entry.Buckets[i], nonces[i], err = key.EncryptWithNonce(inputs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if !prove {
+		return entry, nil
+	}
+
+	// Prove that every bucket encrypts 0 or 1, and that the product of all of
+	// them, whose nonce is the product of the bucket nonces, encrypts 1.
+	entry.Proofs = make([]*paillier.MembershipProof, len(entry.Buckets))
+	var sum, sumNonce = big.NewInt(1), big.NewInt(1)
+	for i, encrypted := range entry.Buckets {
+		if entry.Proofs[i], err = key.ProveMembership(encrypted, inputs[i], nonces[i], bits); err != nil {
+			return nil, err
+		}
+		sum = key.AddEncrypted(sum, encrypted)
+		sumNonce.Mod(sumNonce.Mul(sumNonce, nonces[i]), key.N)
+	}
+
+	if entry.SumProof, err = key.ProveMembership(sum, one[0], sumNonce, one); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Function Verify returns if the provided Entry includes valid proofs that
+// every bucket encrypts a bit and that exactly one of them encrypts 1.
+func (histogram *Histogram) Verify(entry *Entry) bool {
+	var key = histogram.PubKey
+	if len(entry.Buckets) != len(histogram.Counts) || len(entry.Proofs) != len(entry.Buckets) {
+		return false
+	}
+
+	var sum = big.NewInt(1)
+	for i, encrypted := range entry.Buckets {
+		if !key.VerifyMembership(encrypted, bits, entry.Proofs[i]) {
+			return false
+		}
+		sum = key.AddEncrypted(sum, encrypted)
+	}
+
+	return key.VerifyMembership(sum, one, entry.SumProof)
+}
+
+// Function Add adds the encrypted buckets of the Entry provided to the
+// encrypted counts of the histogram. If the entry includes proofs or the
+// histogram requires them, they are verified before adding it. It returns an
+// error if the entry has a wrong number of buckets or if its proofs are not
+// valid.
+func (histogram *Histogram) Add(entry *Entry) error {
+	if len(entry.Buckets) != len(histogram.Counts) {
+		return fmt.Errorf("expected %d buckets, got %d", len(histogram.Counts), len(entry.Buckets))
+	}
+
+	if histogram.RequireProofs || entry.Proofs != nil || entry.SumProof != nil {
+		if !histogram.Verify(entry) {
+			return errors.New("invalid entry proofs")
+		}
+	}
+
+	for i, encrypted := range entry.Buckets {
+		histogram.Counts[i] = histogram.PubKey.AddEncrypted(histogram.Counts[i], encrypted)
+	}
+	histogram.Entries++
+	return nil
+}
+
+// Function Decrypt returns the plain counts of every bucket of the histogram
+// decrypting them with the paillier.PrivateKey provided. It returns an error
+// if the decryption fails.
+func (histogram *Histogram) Decrypt(key *paillier.PrivateKey) ([]int64, error) {
+	var counts = make([]int64, len(histogram.Counts))
+	for i, encrypted := range histogram.Counts {
+		var count, err = key.Decrypt(encrypted)
+		if err != nil {
+			return nil, err
+		}
+		counts[i] = count.Int64()
+	}
+	return counts, nil
+}
@@ -0,0 +1,114 @@
+package histogram
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+var key, _ = paillier.NewKeys(128)
+var boundaries = []float64{0, 10, 20.5}
+
+func TestNew(t *testing.T) {
+	if _, err := New(key.PubKey, []float64{1, 0}); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = New(key.PubKey, []float64{1, 1}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var histogram, err = New(key.PubKey, boundaries)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if len(histogram.Counts) != 4 {
+		t.Fatalf("expected 4, got %d", len(histogram.Counts))
+	}
+
+	var counts, _ = histogram.Decrypt(key)
+	for i, count := range counts {
+		if count != 0 {
+			t.Fatalf("bucket %d: expected 0, got %d", i, count)
+		}
+	}
+}
+
+func TestBucket(t *testing.T) {
+	var histogram, _ = New(key.PubKey, boundaries)
+	var values = []float64{-5, 0, 9.99, 10, 20.4, 20.5, 100}
+	var expected = []int{0, 1, 1, 2, 2, 3, 3}
+	for i, value := range values {
+		if bucket := histogram.Bucket(value); bucket != expected[i] {
+			t.Fatalf("value %v: expected %d, got %d", value, expected[i], bucket)
+		}
+	}
+}
+
+func TestAddDecrypt(t *testing.T) {
+	var histogram, _ = New(key.PubKey, boundaries)
+	var values = []float64{-1, 3, 5, 15, 25, 30, 40, 7.5}
+	for i, value := range values {
+		var entry, err = histogram.Encode(value, i%2 == 0)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if err = histogram.Add(entry); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	}
+
+	var expected = []int64{1, 3, 1, 3}
+	var counts, err = histogram.Decrypt(key)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if histogram.Entries != len(values) {
+		t.Fatalf("expected %d, got %d", len(values), histogram.Entries)
+	}
+	for i, count := range counts {
+		if count != expected[i] {
+			t.Fatalf("bucket %d: expected %d, got %d", i, expected[i], count)
+		}
+	}
+}
+
+func TestProofs(t *testing.T) {
+	var histogram, _ = New(key.PubKey, boundaries)
+	histogram.RequireProofs = true
+
+	var withProofs, _ = histogram.Encode(12, true)
+	var withoutProofs, _ = histogram.Encode(12, false)
+	if !histogram.Verify(withProofs) {
+		t.Fatal("expected valid proofs")
+	} else if err := histogram.Add(withoutProofs); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if err = histogram.Add(&Entry{Buckets: withProofs.Buckets[1:]}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// a bucket that encrypts 2 is detected by the bit proofs
+	var cheat, _ = histogram.Encode(12, true)
+	cheat.Buckets[2] = key.PubKey.Add(cheat.Buckets[2], big.NewInt(1))
+	if histogram.Verify(cheat) {
+		t.Fatal("expected invalid proofs")
+	}
+
+	// two buckets that encrypt 1 are detected by the sum proof
+	var first, _ = histogram.Encode(-1, true)
+	var second, _ = histogram.Encode(12, true)
+	cheat = &Entry{
+		Buckets:  []*big.Int{first.Buckets[0], first.Buckets[1], second.Buckets[2], first.Buckets[3]},
+		Proofs:   []*paillier.MembershipProof{first.Proofs[0], first.Proofs[1], second.Proofs[2], first.Proofs[3]},
+		SumProof: first.SumProof,
+	}
+	if histogram.Verify(cheat) {
+		t.Fatal("expected invalid proofs")
+	} else if err := histogram.Add(cheat); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if err := histogram.Add(withProofs); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	var counts, _ = histogram.Decrypt(key)
+	if counts[2] != 1 || histogram.Entries != 1 {
+		t.Fatalf("expected 1 entry into bucket 2, got %v", counts)
+	}
+}
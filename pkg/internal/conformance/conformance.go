@@ -0,0 +1,116 @@
+// Package conformance provides a shared set of tests to check that the
+// additively homomorphic cryptosystems implemented in this module behave in
+// the same way: encrypt and decrypt signed integers, and add and multiply
+// ciphertexts by other ciphertexts or plaintexts.
+package conformance
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// Interface PublicKey defines the public operations of the cryptosystem to
+// check. It is equivalent to sdk.PublicKey but it is defined here to avoid
+// import cycles between the cryptosystem packages and the sdk.
+type PublicKey interface {
+	Encrypt(input *big.Int) (*big.Int, error)
+	AddEncrypted(a, b *big.Int) *big.Int
+	Add(a, b *big.Int) *big.Int
+	Mul(a, b *big.Int) *big.Int
+}
+
+// Interface PrivateKey defines the decryption operation of the cryptosystem
+// to check. It is equivalent to sdk.PrivateKey.
+type PrivateKey interface {
+	Decrypt(input *big.Int) (*big.Int, error)
+}
+
+// randInt returns a random big.Int in the range [-bound, bound].
+func randInt(t *testing.T, bound *big.Int) *big.Int {
+	var max = new(big.Int).Add(new(big.Int).Lsh(bound, 1), big.NewInt(1))
+	var r, err = rand.Int(rand.Reader, max)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	return r.Sub(r, bound)
+}
+
+// encrypt returns the encryption of the provided input stopping the test if
+// it fails.
+func encrypt(t *testing.T, pubKey PublicKey, input *big.Int) *big.Int {
+	var encrypted, err = pubKey.Encrypt(input)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	return encrypted
+}
+
+// check decrypts the provided encrypted input and compares it with the
+// expected value, stopping the test if they are not equal.
+func check(t *testing.T, key PrivateKey, encrypted, expected *big.Int) {
+	var decrypted, err = key.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if decrypted.Cmp(expected) != 0 {
+		t.Fatalf("expected %d, got %d", expected, decrypted)
+	}
+}
+
+// Function Run executes the conformance tests over the provided key pair using
+// random inputs in the range [-bound, bound]. The results of the operations
+// are always kept into the same range, so the provided bound must ensure that
+// any value into it can be encrypted and decrypted with the key pair.
+func Run(t *testing.T, key PrivateKey, pubKey PublicKey, bound *big.Int) {
+	var iterations = 10
+
+	t.Run("EncryptDecrypt", func(t *testing.T) {
+		var edges = []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(-1),
+			bound, new(big.Int).Neg(bound)}
+		for _, input := range edges {
+			check(t, key, encrypt(t, pubKey, input), input)
+		}
+
+		for i := 0; i < iterations; i++ {
+			var input = randInt(t, bound)
+			check(t, key, encrypt(t, pubKey, input), input)
+		}
+	})
+
+	t.Run("AddEncrypted", func(t *testing.T) {
+		var half = new(big.Int).Rsh(bound, 1)
+		for i := 0; i < iterations; i++ {
+			var a, b = randInt(t, half), randInt(t, half)
+			var encryptedA, encryptedB = encrypt(t, pubKey, a), encrypt(t, pubKey, b)
+			var expected = new(big.Int).Add(a, b)
+			check(t, key, pubKey.AddEncrypted(encryptedA, encryptedB), expected)
+		}
+
+		var a = randInt(t, bound)
+		var encryptedA = encrypt(t, pubKey, a)
+		var encryptedNegA = encrypt(t, pubKey, new(big.Int).Neg(a))
+		check(t, key, pubKey.AddEncrypted(encryptedA, encryptedNegA), big.NewInt(0))
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		var half = new(big.Int).Rsh(bound, 1)
+		for i := 0; i < iterations; i++ {
+			var a, b = randInt(t, half), randInt(t, half)
+			var expected = new(big.Int).Add(a, b)
+			check(t, key, pubKey.Add(encrypt(t, pubKey, a), b), expected)
+		}
+	})
+
+	t.Run("Mul", func(t *testing.T) {
+		var root = new(big.Int).Sqrt(bound)
+		for i := 0; i < iterations; i++ {
+			var a, b = randInt(t, root), randInt(t, root)
+			var expected = new(big.Int).Mul(a, b)
+			check(t, key, pubKey.Mul(encrypt(t, pubKey, a), b), expected)
+		}
+
+		var a = randInt(t, bound)
+		check(t, key, pubKey.Mul(encrypt(t, pubKey, a), big.NewInt(0)), big.NewInt(0))
+		check(t, key, pubKey.Mul(encrypt(t, pubKey, a), big.NewInt(-1)), new(big.Int).Neg(a))
+	})
+}
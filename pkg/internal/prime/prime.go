@@ -0,0 +1,56 @@
+// Package prime provides a cancellable generation of random prime numbers,
+// equivalent to crypto/rand.Prime but checking the provided context between
+// candidates, so long key generations can be aborted.
+package prime
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// Function Generate returns a random prime number of the provided number of
+// bits, with its two most significant bits set, as crypto/rand.Prime does, so
+// the product of two of them has exactly twice the number of bits. It returns
+// ctx.Err() if the provided context is cancelled before a prime is found, or
+// an error if the number of bits is lower than 2 or if the random source
+// fails.
+func Generate(ctx context.Context, bits int) (*big.Int, error) {
+	if bits < 2 {
+		return nil, errors.New("prime size must be at least 2-bit")
+	}
+
+	var b = uint(bits % 8)
+	if b == 0 {
+		b = 8
+	}
+
+	var bytes = make([]byte, (bits+7)/8)
+	var p = new(big.Int)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		} else if _, err = rand.Read(bytes); err != nil {
+			return nil, err
+		}
+
+		// Clear the bits in the first byte to make sure the candidate has the
+		// requested size, and set the two most significant bits.
+		bytes[0] &= uint8(int(1<<b) - 1)
+		if b >= 2 {
+			bytes[0] |= 3 << (b - 2)
+		} else {
+			bytes[0] |= 1
+			if len(bytes) > 1 {
+				bytes[1] |= 0x80
+			}
+		}
+		// Make the candidate odd.
+		bytes[len(bytes)-1] |= 1
+
+		if p.SetBytes(bytes); p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package prime
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, bits := range []int{2, 9, 16, 64, 257} {
+		var p, err = Generate(context.Background(), bits)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if p.BitLen() != bits {
+			t.Fatalf("expected %d bits, got %d", bits, p.BitLen())
+		} else if !p.ProbablyPrime(20) {
+			t.Fatalf("expected prime, got %s", p)
+		}
+	}
+
+	if _, err := Generate(context.Background(), 1); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := Generate(ctx, 1024); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	}
+}
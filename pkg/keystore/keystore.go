@@ -0,0 +1,297 @@
+// Package keystore allows to store and rotate paillier.PrivateKey's, encrypted
+// at rest under a passphrase. Every key is identified by the fingerprint of
+// its paillier.PublicKey and has a state: the active key is used to encrypt
+// new numbers, while the retired ones are only used to decrypt the numbers
+// encrypted before a rotation. The KeyStore implements the sdk.KeyResolver
+// interface, so an sdk.Client can decrypt any number.Number using the key
+// identified by its KeyID. The keys are persisted through a Storage, a
+// file-backed implementation is provided, but any other backend could be used.
+package keystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// States of the keys of a KeyStore.
+const (
+	Active  = "active"
+	Retired = "retired"
+)
+
+// ErrNotFound is returned when the requested key does not exist.
+var ErrNotFound = errors.New("key not found")
+
+// Interface Storage defines how the KeyStore persists its records, allowing to
+// use any backend. Load must return ErrNotFound if the record does not exist.
+type Storage interface {
+	Save(id string, data []byte) error
+	Load(id string) ([]byte, error)
+	List() ([]string, error)
+}
+
+// Struct Entry contains the public information of a stored key: its
+// identifier, its state, its creation time and its paillier.PublicKey.
+type Entry struct {
+	ID      string              `json:"id"`
+	State   string              `json:"state"`
+	Created time.Time           `json:"created"`
+	PubKey  *paillier.PublicKey `json:"pubKey"`
+}
+
+// record is the persisted representation of a key: its Entry and the
+// paillier.PrivateKey exported under the passphrase of the KeyStore.
+type record struct {
+	Entry
+	Key json.RawMessage `json:"key"`
+}
+
+// Struct KeyStore contains the Storage where the keys are persisted, the
+// passphrase used to encrypt them at rest and the cache of the keys already
+// decrypted, to derive the passphrase key only once per key. It is safe for
+// concurrent use.
+type KeyStore struct {
+	Storage    Storage
+	passphrase []byte
+	keys       map[string]*paillier.PrivateKey
+	mtx        sync.Mutex
+}
+
+// Function New returns a new KeyStore over the provided Storage, that encrypts
+// the keys with the provided passphrase. It returns an error if the
+// passphrase is empty.
+func New(storage Storage, passphrase string) (*KeyStore, error) {
+	if passphrase == "" {
+		return nil, errors.New("the passphrase must not be empty")
+	}
+	return &KeyStore{
+		Storage:    storage,
+		passphrase: []byte(passphrase),
+		keys:       make(map[string]*paillier.PrivateKey),
+	}, nil
+}
+
+// Function Open returns a new KeyStore that persists the keys into the
+// provided directory, encrypted with the provided passphrase.
+func Open(dir, passphrase string) (*KeyStore, error) {
+	return New(&FileStorage{Dir: dir}, passphrase)
+}
+
+// load returns the record of the key with the provided identifier.
+func (ks *KeyStore) load(id string) (*record, error) {
+	var data, err = ks.Storage.Load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var rec = new(record)
+	if err = json.Unmarshal(data, rec); err != nil {
+		return nil, fmt.Errorf("malformed key record '%s': %w", id, err)
+	}
+	return rec, nil
+}
+
+// save persists the provided record.
+func (ks *KeyStore) save(rec *record) error {
+	var data, err = json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return ks.Storage.Save(rec.ID, data)
+}
+
+// records returns all the records of the KeyStore sorted by creation time.
+func (ks *KeyStore) records() ([]*record, error) {
+	var ids, err = ks.Storage.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var recs = make([]*record, 0, len(ids))
+	for _, id := range ids {
+		var rec *record
+		if rec, err = ks.load(id); err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+
+	sort.SliceStable(recs, func(i, j int) bool {
+		return recs[i].Created.Before(recs[j].Created)
+	})
+	return recs, nil
+}
+
+// Function Add stores the provided paillier.PrivateKey, exported with
+// paillier.PrivateKey.Export under the passphrase of the KeyStore, as the
+// active key, retiring the previous active key, and returns its Entry. It
+// also clears the cache of decrypted keys, so the retired ones are only kept
+// in memory again if they are used. It returns an error if the key is already
+// stored or if the Storage fails.
+func (ks *KeyStore) Add(key *paillier.PrivateKey) (*Entry, error) {
+	ks.mtx.Lock()
+	defer ks.mtx.Unlock()
+
+	var id = key.PubKey.Fingerprint()
+	if _, err := ks.load(id); err == nil {
+		return nil, fmt.Errorf("key '%s' already stored", id)
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	var rec = &record{Entry: Entry{ID: id, State: Active, Created: time.Now().UTC(), PubKey: key.PubKey}}
+	var err error
+	if rec.Key, err = key.Export(ks.passphrase); err != nil {
+		return nil, err
+	}
+
+	var recs []*record
+	if recs, err = ks.records(); err != nil {
+		return nil, err
+	}
+	for _, other := range recs {
+		if other.State == Active {
+			other.State = Retired
+			if err = ks.save(other); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = ks.save(rec); err != nil {
+		return nil, err
+	}
+
+	ks.keys = make(map[string]*paillier.PrivateKey)
+	return &rec.Entry, nil
+}
+
+// Function Rotate generates a new paillier.PrivateKey of the provided size,
+// stores it as the active key retiring the previous one, and returns its
+// Entry. It returns an error if the key generation or the Storage fail.
+func (ks *KeyStore) Rotate(size int) (*Entry, error) {
+	var key, err = paillier.NewKeys(size)
+	if err != nil {
+		return nil, err
+	}
+	return ks.Add(key)
+}
+
+// Function Retire changes the state of the key with the provided identifier
+// to retired, so it is only used to decrypt. It returns an error if the key
+// does not exist or if the Storage fails.
+func (ks *KeyStore) Retire(id string) error {
+	ks.mtx.Lock()
+	defer ks.mtx.Unlock()
+
+	var rec, err = ks.load(id)
+	if err != nil {
+		return err
+	}
+
+	rec.State = Retired
+	return ks.save(rec)
+}
+
+// Function Entries returns the Entry of every stored key, sorted by creation
+// time.
+func (ks *KeyStore) Entries() ([]*Entry, error) {
+	ks.mtx.Lock()
+	defer ks.mtx.Unlock()
+
+	var recs, err = ks.records()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries = make([]*Entry, len(recs))
+	for i, rec := range recs {
+		entries[i] = &rec.Entry
+	}
+	return entries, nil
+}
+
+// Function Active returns the Entry of the active key. It returns ErrNotFound
+// if there is no active key.
+func (ks *KeyStore) Active() (*Entry, error) {
+	var entries, err = ks.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.State == Active {
+			return entry, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Function Key returns the paillier.PrivateKey with the provided identifier,
+// decrypting it with the passphrase of the KeyStore. The decrypted keys are
+// cached until the next call to KeyStore.Add or KeyStore.Rotate, because the
+// key derivation of the passphrase is expensive. It returns an error if the
+// key does not exist, if the passphrase is wrong or if the stored key is
+// corrupted.
+func (ks *KeyStore) Key(id string) (*paillier.PrivateKey, error) {
+	ks.mtx.Lock()
+	defer ks.mtx.Unlock()
+	if key, ok := ks.keys[id]; ok {
+		return key, nil
+	}
+
+	var rec, err = ks.load(id)
+	if err != nil {
+		return nil, err
+	} else if len(rec.Key) == 0 {
+		return nil, fmt.Errorf("key '%s' has no private key", id)
+	}
+
+	var key *paillier.PrivateKey
+	if key, err = paillier.ImportPrivateKey(rec.Key, ks.passphrase); err != nil {
+		return nil, err
+	} else if key.PubKey.Fingerprint() != id {
+		return nil, fmt.Errorf("key '%s' does not match its identifier", id)
+	}
+
+	ks.keys[id] = key
+	return key, nil
+}
+
+// Function PrivateKey implements the sdk.KeyResolver interface, returning the
+// paillier.PrivateKey with the provided identifier as sdk.PrivateKey.
+func (ks *KeyStore) PrivateKey(id string) (sdk.PrivateKey, error) {
+	var key, err = ks.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Function Client returns an sdk.Client with the active key of the KeyStore,
+// that assigns its identifier to the encrypted numbers and uses the KeyStore
+// to decrypt the numbers encrypted with retired keys. It returns an error if
+// there is no active key or if it can not be decrypted.
+func (ks *KeyStore) Client() (*sdk.Client, error) {
+	var entry, err = ks.Active()
+	if err != nil {
+		return nil, err
+	}
+
+	var key *paillier.PrivateKey
+	if key, err = ks.Key(entry.ID); err != nil {
+		return nil, err
+	}
+
+	var client = sdk.NewClient(key, key.PubKey)
+	client.KeyID = entry.ID
+	client.Keys = ks
+	return client, nil
+}
@@ -0,0 +1,179 @@
+package keystore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+func TestRotation(t *testing.T) {
+	var dir = t.TempDir()
+	var ks, err = Open(dir, "passphrase")
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if _, err = ks.Active(); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %s, got %v", ErrNotFound, err)
+	}
+
+	var first, second *Entry
+	if first, err = ks.Rotate(64); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if first.ID != first.PubKey.Fingerprint() || first.State != Active {
+		t.Fatalf("unexpected entry %+v", first)
+	}
+
+	var oldClient *sdk.Client
+	if oldClient, err = ks.Client(); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	var oldEncrypted, _ = oldClient.Encrypt(new(number.Number).SetInt(42))
+	if oldEncrypted.KeyID != first.ID {
+		t.Fatalf("expected %s, got %s", first.ID, oldEncrypted.KeyID)
+	}
+
+	if second, err = ks.Rotate(64); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var entries []*Entry
+	if entries, err = ks.Entries(); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if len(entries) != 2 || entries[0].ID != first.ID || entries[0].State != Retired || entries[1].ID != second.ID || entries[1].State != Active {
+		t.Fatalf("unexpected entries %+v %+v", entries[0], entries[1])
+	}
+
+	// the new client decrypts numbers encrypted with the retired key
+	var client *sdk.Client
+	if client, err = ks.Client(); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if client.KeyID != second.ID {
+		t.Fatalf("expected %s, got %s", second.ID, client.KeyID)
+	}
+
+	var newEncrypted, _ = client.Encrypt(new(number.Number).SetInt(7))
+	for expected, encrypted := range map[int64]*number.Number{42: oldEncrypted, 7: newEncrypted} {
+		if decrypted, err := client.Decrypt(encrypted); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if decrypted.Int() != expected || decrypted.KeyID != "" {
+			t.Fatalf("expected %d, got %d", expected, decrypted.Int())
+		}
+	}
+
+	// reopen the store from disk
+	var reopened, _ = Open(dir, "passphrase")
+	if active, err := reopened.Active(); err != nil || active.ID != second.ID {
+		t.Fatalf("expected %s, got %v", second.ID, err)
+	}
+
+	if err = ks.Retire(second.ID); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if _, err = ks.Client(); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %s, got %v", ErrNotFound, err)
+	}
+}
+
+// countingStorage counts the records loaded from the Storage.
+type countingStorage struct {
+	Storage
+	loads int
+}
+
+func (storage *countingStorage) Load(id string) ([]byte, error) {
+	storage.loads++
+	return storage.Storage.Load(id)
+}
+
+func TestKeyCache(t *testing.T) {
+	var storage = &countingStorage{Storage: &FileStorage{Dir: t.TempDir()}}
+	var ks, _ = New(storage, "passphrase")
+	var first, _ = ks.Rotate(64)
+	var client, _ = ks.Client()
+	var encrypted, _ = client.Encrypt(new(number.Number).SetInt(42))
+	ks.Rotate(64)
+	client, _ = ks.Client()
+
+	// the retired key is only loaded and decrypted the first time
+	var loads = storage.loads
+	for i := 0; i < 3; i++ {
+		if decrypted, err := client.Decrypt(encrypted); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if decrypted.Int() != 42 {
+			t.Fatalf("expected 42, got %d", decrypted.Int())
+		}
+	}
+	if storage.loads != loads+1 {
+		t.Fatalf("expected %d loads, got %d", loads+1, storage.loads)
+	}
+
+	// the rotation clears the cache
+	ks.Rotate(64)
+	loads = storage.loads
+	if _, err := ks.Key(first.ID); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if storage.loads != loads+1 {
+		t.Fatalf("expected %d loads, got %d", loads+1, storage.loads)
+	}
+}
+
+func TestEncryptedAtRest(t *testing.T) {
+	var dir = t.TempDir()
+	var ks, _ = Open(dir, "passphrase")
+	var entry, err = ks.Rotate(64)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var path = filepath.Join(dir, entry.ID+".json")
+	var info os.FileInfo
+	if info, err = os.Stat(path); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected 0600, got %o", info.Mode().Perm())
+	}
+
+	var data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), `"d"`) {
+		t.Fatal("expected the private key to be encrypted")
+	}
+
+	var wrong, _ = Open(dir, "wrong")
+	if _, err = wrong.Key(entry.ID); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = wrong.Client(); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var key, _ = ks.Key(entry.ID)
+	if _, err = ks.Add(key); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := Open(t.TempDir(), ""); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var ks, _ = Open(t.TempDir(), "passphrase")
+	if _, err := ks.Key("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %s, got %v", ErrNotFound, err)
+	} else if err = ks.Retire("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %s, got %v", ErrNotFound, err)
+	} else if _, err = ks.Key("../key"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// numbers encrypted with unknown keys can not be decrypted
+	ks.Rotate(64)
+	var client, _ = ks.Client()
+	var encrypted, _ = client.Encrypt(new(number.Number).SetInt(1))
+	encrypted.KeyID = "unknown"
+	if _, err := client.Decrypt(encrypted); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,82 @@
+package keystore
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// extension is the file extension of the records of a FileStorage.
+const extension = ".json"
+
+// Struct FileStorage implements the Storage interface persisting every record
+// as a file into the directory Dir, readable and writable only by the owner.
+type FileStorage struct {
+	Dir string
+}
+
+// path returns the path of the file of the record with the provided
+// identifier. It returns an error if the identifier is not a valid file name.
+func (storage *FileStorage) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", errors.New("invalid key identifier")
+	}
+	return filepath.Join(storage.Dir, id+extension), nil
+}
+
+// Function Save writes the provided data into the file of the record with the
+// provided identifier, creating the directory if it does not exist. The file
+// is written into a temporary file first and then renamed, so a failed write
+// does not corrupt the previous record.
+func (storage *FileStorage) Save(id string, data []byte) error {
+	var path, err = storage.path(id)
+	if err != nil {
+		return err
+	} else if err = os.MkdirAll(storage.Dir, 0700); err != nil {
+		return err
+	}
+
+	var tmp = path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Function Load returns the data of the record with the provided identifier.
+// It returns ErrNotFound if the record does not exist.
+func (storage *FileStorage) Load(id string) ([]byte, error) {
+	var path, err = storage.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if data, err = os.ReadFile(path); errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Function List returns the identifiers of all the stored records, sorted
+// alphabetically. It returns an empty list if the directory does not exist.
+func (storage *FileStorage) List() ([]string, error) {
+	var files, err = os.ReadDir(storage.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, file := range files {
+		if name := file.Name(); !file.IsDir() && strings.HasSuffix(name, extension) {
+			ids = append(ids, strings.TrimSuffix(name, extension))
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,273 @@
+// Package ml allows to compute machine learning models over encrypted data.
+// A LinearModel with plain weights predicts over encrypted features, while an
+// EncryptedLinearModel, with encrypted weights, predicts over plain features:
+//
+//	E(y) = E(b) + Σ w_i * E(x_i)    or    E(y) = E(b) + Σ E(w_i) * x_i
+//
+// Every homomorphic multiplication adds the exponents of both operands and
+// every addition aligns them to the lowest one, so the weights (or the
+// features) could be rounded to a fixed number of decimals to limit the
+// growth of the exponent and of the encrypted value. The prediction of a
+// LinearModel could also be checked against the plaintext limit of the key
+// using a bound of the features, to avoid silent overflows.
+//
+// Linear models can also be trained over data split across several parties:
+// every party encrypts its sufficient statistics, XᵀX and Xᵀy, the statistics
+// are aggregated homomorphically and the keyholder only decrypts the
+// aggregate to solve the normal equations.
+package ml
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+var ten = big.NewInt(10)
+
+// Struct LinearModel contains the plain weights and intercept of a linear
+// model. If both FeatureBound and Limit are defined, every prediction over
+// encrypted features checks that the absolute value of the encrypted result
+// can not exceed Limit when the absolute value of every feature is lower or
+// equal than FeatureBound.
+type LinearModel struct {
+	Weights      []*number.Number
+	Intercept    *number.Number
+	FeatureBound *number.Number
+	Limit        *big.Int
+}
+
+// Struct EncryptedLinearModel contains the sdk.PublicKey and the encrypted
+// weights and intercept of a linear model. If Precision is greater or equal
+// than zero, the plain features are rounded to Precision decimals before the
+// prediction.
+type EncryptedLinearModel struct {
+	PubKey    sdk.PublicKey
+	Weights   []*number.Number
+	Intercept *number.Number
+	Precision int
+}
+
+// Function PaillierLimit returns the maximum absolute value that an encrypted
+// number.Number value could have to be decrypted correctly with the provided
+// paillier.PublicKey, ⌊N/2⌋.
+func PaillierLimit(pubKey *paillier.PublicKey) *big.Int {
+	return new(big.Int).Rsh(pubKey.N, 1)
+}
+
+// Function Round returns a new plain number.Number with the value of the
+// provided one rounded to the provided number of decimals, half away from
+// zero, and normalized. It returns an error if the number provided is
+// encrypted or if the number of decimals is negative.
+func Round(num *number.Number, decimals int) (*number.Number, error) {
+	if num.IsEncrypted() {
+		return nil, errors.New("the Number provided must not be encrypted")
+	} else if decimals < 0 {
+		return nil, errors.New("the number of decimals must not be negative")
+	}
+
+	var result = new(number.Number).Set(num)
+	var minExp = big.NewInt(int64(-decimals))
+	if num.Exp.Cmp(minExp) < 0 {
+		var diff = new(big.Int).Sub(minExp, num.Exp)
+		var factor = new(big.Int).Exp(ten, diff, nil)
+		var quo, rem = new(big.Int).QuoRem(num.Value, factor, new(big.Int))
+		if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(factor) >= 0 {
+			quo.Add(quo, big.NewInt(int64(num.Value.Sign())))
+		}
+		result.Value, result.Exp = quo, minExp
+	}
+	return result.Normalize()
+}
+
+// Function NewLinearModel returns a new LinearModel with the provided plain
+// weights and intercept. If precision is greater or equal than zero, the
+// weights and the intercept are rounded to that number of decimals, otherwise
+// they are only normalized. It returns an error if there are no weights or if
+// any parameter is encrypted.
+func NewLinearModel(weights []*number.Number, intercept *number.Number, precision int) (*LinearModel, error) {
+	if len(weights) == 0 {
+		return nil, errors.New("no weights provided")
+	} else if intercept == nil {
+		return nil, errors.New("no intercept provided")
+	}
+
+	var params, err = prepare(append(append([]*number.Number{}, weights...), intercept), precision)
+	if err != nil {
+		return nil, err
+	}
+	return &LinearModel{Weights: params[:len(weights)], Intercept: params[len(weights)]}, nil
+}
+
+// prepare returns a copy of the provided plain numbers rounded to the
+// provided number of decimals, or only normalized if precision is negative.
+func prepare(inputs []*number.Number, precision int) ([]*number.Number, error) {
+	var result = make([]*number.Number, len(inputs))
+	for i, input := range inputs {
+		if input.IsEncrypted() {
+			return nil, fmt.Errorf("parameter %d must not be encrypted", i)
+		}
+
+		var err error
+		if precision >= 0 {
+			result[i], err = Round(input, precision)
+		} else {
+			result[i], err = new(number.Number).Set(input).Normalize()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Function Predict returns the plain prediction of the current LinearModel
+// for the provided plain features. It returns an error if the number of
+// features does not match the number of weights or if any feature is
+// encrypted.
+func (model *LinearModel) Predict(features []*number.Number) (*number.Number, error) {
+	if len(features) != len(model.Weights) {
+		return nil, errors.New("the number of features does not match the number of weights")
+	}
+
+	var result = new(number.Number).Set(model.Intercept)
+	for i, feature := range features {
+		var term, err = new(number.Number).Mul(model.Weights[i], feature)
+		if err != nil {
+			return nil, err
+		} else if result, err = new(number.Number).Add(result, term); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Function PredictEncrypted returns the encrypted prediction of the current
+// LinearModel for the provided encrypted features, using the provided
+// sdk.PublicKey. It returns an error if the number of features does not match
+// the number of weights, if any feature is not encrypted or if the
+// prediction could overflow the Limit of the model.
+func (model *LinearModel) PredictEncrypted(pubKey sdk.PublicKey, features []*number.Number) (*number.Number, error) {
+	if len(features) != len(model.Weights) {
+		return nil, errors.New("the number of features does not match the number of weights")
+	}
+
+	for i, feature := range features {
+		if !feature.IsEncrypted() {
+			return nil, fmt.Errorf("feature %d must be encrypted", i)
+		}
+	}
+
+	if model.FeatureBound != nil && model.Limit != nil {
+		if bound := model.bound(features); bound.Cmp(model.Limit) > 0 {
+			return nil, errors.New("the prediction could overflow the plaintext limit")
+		}
+	}
+
+	var result *number.Number
+	for i, feature := range features {
+		var term, err = sdk.Mul(pubKey, feature, model.Weights[i])
+		if err != nil {
+			return nil, err
+		} else if result == nil {
+			result = term
+		} else if result, err = sdk.AddEncrypted(pubKey, result, term); err != nil {
+			return nil, err
+		}
+	}
+	return sdk.Add(pubKey, result, model.Intercept)
+}
+
+// bound returns the maximum absolute value that the encrypted prediction
+// could have for the provided encrypted features, if the absolute value of
+// every feature is lower or equal than the FeatureBound of the model. Every
+// term is bounded by |w_i.Value| * |B.Value| * 10^(w_i.Exp + B.Exp - E), where
+// E is the lowest exponent of the terms and the intercept, that the result
+// will have after the alignments.
+func (model *LinearModel) bound(features []*number.Number) *big.Int {
+	var exps = make([]*big.Int, len(features))
+	var minExp = model.Intercept.Exp
+	for i, feature := range features {
+		exps[i] = new(big.Int).Add(model.Weights[i].Exp, feature.Exp)
+		if exps[i].Cmp(minExp) < 0 {
+			minExp = exps[i]
+		}
+	}
+
+	var boundValue = new(big.Int).Abs(model.FeatureBound.Value)
+	var total = scale(new(big.Int).Abs(model.Intercept.Value), new(big.Int).Sub(model.Intercept.Exp, minExp))
+	for _, weight := range model.Weights {
+		var value = new(big.Int).Mul(new(big.Int).Abs(weight.Value), boundValue)
+		var exp = new(big.Int).Sub(new(big.Int).Add(weight.Exp, model.FeatureBound.Exp), minExp)
+		total.Add(total, scale(value, exp))
+	}
+	return total
+}
+
+// scale returns ⌈value * 10^exp⌉ for the provided non negative value.
+func scale(value, exp *big.Int) *big.Int {
+	var factor = new(big.Int).Exp(ten, new(big.Int).Abs(exp), nil)
+	if exp.Sign() >= 0 {
+		return factor.Mul(factor, value)
+	}
+
+	var quo, rem = new(big.Int).QuoRem(value, factor, new(big.Int))
+	if rem.Sign() != 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	return quo
+}
+
+// Function Encrypt returns the EncryptedLinearModel of the current
+// LinearModel, encrypting its weights and intercept with the provided
+// sdk.PublicKey and using the provided precision for the features. It returns
+// an error if the encryption fails.
+func (model *LinearModel) Encrypt(pubKey sdk.PublicKey, precision int) (*EncryptedLinearModel, error) {
+	var client = sdk.NewClient(nil, pubKey)
+	var encrypted = &EncryptedLinearModel{
+		PubKey:    pubKey,
+		Weights:   make([]*number.Number, len(model.Weights)),
+		Precision: precision,
+	}
+
+	var err error
+	for i, weight := range model.Weights {
+		if encrypted.Weights[i], err = client.Encrypt(weight); err != nil {
+			return nil, err
+		}
+	}
+	if encrypted.Intercept, err = client.Encrypt(model.Intercept); err != nil {
+		return nil, err
+	}
+	return encrypted, nil
+}
+
+// Function Predict returns the encrypted prediction of the current
+// EncryptedLinearModel for the provided plain features. It returns an error if
+// the number of features does not match the number of weights or if any
+// feature is encrypted.
+func (model *EncryptedLinearModel) Predict(features []*number.Number) (*number.Number, error) {
+	if len(features) != len(model.Weights) {
+		return nil, errors.New("the number of features does not match the number of weights")
+	}
+
+	var inputs, err = prepare(features, model.Precision)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = model.Intercept
+	for i, input := range inputs {
+		var term *number.Number
+		if term, err = sdk.Mul(model.PubKey, model.Weights[i], input); err != nil {
+			return nil, err
+		} else if result, err = sdk.AddEncrypted(model.PubKey, result, term); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,159 @@
+package ml
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+var client, _ = sdk.InitClient(256)
+
+func parse(t *testing.T, inputs ...string) []*number.Number {
+	var result = make([]*number.Number, len(inputs))
+	for i, input := range inputs {
+		var err error
+		if result[i], err = new(number.Number).SetString(input); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	}
+	return result
+}
+
+func encrypt(t *testing.T, inputs []*number.Number) []*number.Number {
+	var result = make([]*number.Number, len(inputs))
+	for i, input := range inputs {
+		var err error
+		if result[i], err = client.Encrypt(input); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	}
+	return result
+}
+
+func TestRound(t *testing.T) {
+	var tests = []struct {
+		input    string
+		decimals int
+		expected string
+	}{
+		{"1.23456", 2, "1.23"},
+		{"1.235", 2, "1.24"},
+		{"-1.235", 2, "-1.24"},
+		{"-0.004", 2, "0"},
+		{"120", 0, "120"},
+		{"0.5", 0, "1"},
+	}
+
+	for _, test := range tests {
+		var result, err = Round(parse(t, test.input)[0], test.decimals)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if result.String() != test.expected {
+			t.Fatalf("%s: expected %s, got %s", test.input, test.expected, result)
+		}
+	}
+
+	if _, err := Round(parse(t, "1")[0], -1); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestPredictEncrypted(t *testing.T) {
+	var model, err = NewLinearModel(parse(t, "0.5", "-1.25", "3"), parse(t, "10")[0], -1)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var features = parse(t, "2", "0.4", "-1.5")
+	var expected, _ = model.Predict(features)
+	var encrypted *number.Number
+	if encrypted, err = model.PredictEncrypted(client.PubKey, encrypt(t, features)); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var decrypted, _ = client.Decrypt(encrypted)
+	if expected, _ = expected.Normalize(); expected.String() != "6" {
+		t.Fatalf("expected 6, got %s", expected)
+	} else if cmp, _ := decrypted.Cmp(expected); cmp != 0 {
+		t.Fatalf("expected 6, got %s", decrypted)
+	}
+}
+
+func TestPredictPrecision(t *testing.T) {
+	var model, _ = NewLinearModel(parse(t, "0.123456789", "2.000001"), parse(t, "-0.3333333")[0], 2)
+	if model.Weights[0].String() != "0.12" || model.Weights[1].String() != "2" || model.Intercept.String() != "-0.33" {
+		t.Fatalf("unexpected rounded model %s %s %s", model.Weights[0], model.Weights[1], model.Intercept)
+	}
+
+	var encrypted, err = model.PredictEncrypted(client.PubKey, encrypt(t, parse(t, "100", "0.5")))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var decrypted, _ = client.Decrypt(encrypted)
+	if decrypted, _ = decrypted.Normalize(); decrypted.String() != "12.67" {
+		t.Fatalf("expected 12.67, got %s", decrypted)
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	var model, _ = NewLinearModel(parse(t, "1000", "0.001"), parse(t, "1")[0], -1)
+	var features = encrypt(t, parse(t, "1.5", "2"))
+
+	var key = client.PubKey.(*paillier.PublicKey)
+	model.FeatureBound = parse(t, "10")[0]
+	model.Limit = PaillierLimit(key)
+	if _, err := model.PredictEncrypted(client.PubKey, features); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	model.Limit = big.NewInt(10000)
+	if _, err := model.PredictEncrypted(client.PubKey, features); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestEncryptedModel(t *testing.T) {
+	var model, _ = NewLinearModel(parse(t, "1.5", "-2", "0.25"), parse(t, "0.75")[0], -1)
+	var encryptedModel, err = model.Encrypt(client.PubKey, 3)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var features = parse(t, "4", "1.0004", "8")
+	var encrypted *number.Number
+	if encrypted, err = encryptedModel.Predict(features); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	// the second feature is rounded to 1
+	var decrypted, _ = client.Decrypt(encrypted)
+	if decrypted, _ = decrypted.Normalize(); decrypted.String() != "6.75" {
+		t.Fatalf("expected 6.75, got %s", decrypted)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewLinearModel(nil, parse(t, "1")[0], -1); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewLinearModel(parse(t, "1"), nil, -1); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewLinearModel(encrypt(t, parse(t, "1")), parse(t, "1")[0], -1); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var model, _ = NewLinearModel(parse(t, "1", "2"), parse(t, "1")[0], -1)
+	var encryptedModel, _ = model.Encrypt(client.PubKey, -1)
+	if _, err := model.PredictEncrypted(client.PubKey, encrypt(t, parse(t, "1"))); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = model.PredictEncrypted(client.PubKey, parse(t, "1", "2")); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = encryptedModel.Predict(parse(t, "1")); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = encryptedModel.Predict(encrypt(t, parse(t, "1", "2"))); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,286 @@
+package ml
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// Struct Statistics contains the encrypted sufficient statistics of a linear
+// regression over a dataset, XᵀX and Xᵀy, the number of samples used to
+// compute them and if X includes a column of ones to fit the intercept. The
+// statistics of several parties encrypted with the same key can be aggregated
+// without decrypting them, and only the keyholder can decrypt the aggregate
+// to fit the model.
+type Statistics struct {
+	XtX       [][]*number.Number
+	Xty       []*number.Number
+	Samples   int
+	Intercept bool
+}
+
+// Function EncryptStatistics computes the sufficient statistics XᵀX and Xᵀy of
+// the provided plain features and targets, adding a column of ones to the
+// features if intercept is true, and returns them encrypted with the provided
+// sdk.PublicKey. It returns an error if there are no samples, if the samples
+// have different number of features, if the number of samples and targets is
+// different, if any value is encrypted or if the encryption fails.
+func EncryptStatistics(pubKey sdk.PublicKey, features [][]*number.Number, targets []*number.Number, intercept bool) (*Statistics, error) {
+	if len(features) == 0 {
+		return nil, errors.New("no samples provided")
+	} else if len(features) != len(targets) {
+		return nil, errors.New("the number of samples and targets must be the same")
+	}
+
+	var rows = make([][]*number.Number, len(features))
+	for i, sample := range features {
+		if len(sample) != len(features[0]) {
+			return nil, fmt.Errorf("sample %d has a different number of features", i)
+		}
+
+		rows[i] = sample
+		if intercept {
+			rows[i] = append([]*number.Number{new(number.Number).SetInt(1)}, sample...)
+		}
+	}
+
+	var size = len(rows[0])
+	var client = sdk.NewClient(nil, pubKey)
+	var stats = &Statistics{
+		XtX:       make([][]*number.Number, size),
+		Xty:       make([]*number.Number, size),
+		Samples:   len(rows),
+		Intercept: intercept,
+	}
+
+	for i := 0; i < size; i++ {
+		stats.XtX[i] = make([]*number.Number, size)
+		for j := 0; j < size; j++ {
+			var sum, err = dot(rows, i, j, nil)
+			if err != nil {
+				return nil, err
+			} else if stats.XtX[i][j], err = client.Encrypt(sum); err != nil {
+				return nil, err
+			}
+		}
+
+		var sum, err = dot(rows, i, -1, targets)
+		if err != nil {
+			return nil, err
+		} else if stats.Xty[i], err = client.Encrypt(sum); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+// dot returns the plain dot product between the column i of the provided rows
+// and their column j, or the provided targets if j is negative.
+func dot(rows [][]*number.Number, i, j int, targets []*number.Number) (*number.Number, error) {
+	var result = new(number.Number).SetInt(0)
+	for r, row := range rows {
+		var other *number.Number
+		if j < 0 {
+			other = targets[r]
+		} else {
+			other = row[j]
+		}
+
+		var term, err = new(number.Number).Mul(row[i], other)
+		if err != nil {
+			return nil, err
+		} else if result, err = new(number.Number).Add(result, term); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// checkShape returns an error if the provided Statistics are nil, if XᵀX is
+// not a square matrix with the size of Xᵀy or if any of their values is nil.
+func checkShape(stats *Statistics) error {
+	if stats == nil {
+		return errors.New("no statistics provided")
+	}
+
+	var size = len(stats.Xty)
+	if len(stats.XtX) != size {
+		return fmt.Errorf("XᵀX must have %d rows, got %d", size, len(stats.XtX))
+	}
+	for i, row := range stats.XtX {
+		if len(row) != size {
+			return fmt.Errorf("row %d of XᵀX must have %d columns, got %d", i, size, len(row))
+		}
+		for _, value := range row {
+			if value == nil {
+				return fmt.Errorf("row %d of XᵀX has missing values", i)
+			}
+		}
+		if stats.Xty[i] == nil {
+			return errors.New("Xᵀy has missing values")
+		}
+	}
+	return nil
+}
+
+// Function AggregateStatistics returns the encrypted addition of the provided
+// Statistics, using the provided sdk.PublicKey. It returns an error if no
+// Statistics are provided, if XᵀX is not a square matrix with the size of Xᵀy
+// or if they have different dimensions or intercept configuration.
+func AggregateStatistics(pubKey sdk.PublicKey, stats ...*Statistics) (*Statistics, error) {
+	if len(stats) == 0 {
+		return nil, errors.New("no statistics provided")
+	}
+	for _, other := range stats {
+		if err := checkShape(other); err != nil {
+			return nil, err
+		}
+	}
+
+	var size = len(stats[0].Xty)
+	var result = &Statistics{
+		XtX:       make([][]*number.Number, size),
+		Xty:       append([]*number.Number{}, stats[0].Xty...),
+		Samples:   stats[0].Samples,
+		Intercept: stats[0].Intercept,
+	}
+	for i := range result.XtX {
+		result.XtX[i] = append([]*number.Number{}, stats[0].XtX[i]...)
+	}
+
+	for _, other := range stats[1:] {
+		if len(other.Xty) != size || other.Intercept != result.Intercept {
+			return nil, errors.New("all the statistics must have the same dimensions and intercept")
+		}
+
+		var err error
+		for i := 0; i < size; i++ {
+			for j := 0; j < size; j++ {
+				if result.XtX[i][j], err = sdk.AddEncrypted(pubKey, result.XtX[i][j], other.XtX[i][j]); err != nil {
+					return nil, err
+				}
+			}
+			if result.Xty[i], err = sdk.AddEncrypted(pubKey, result.Xty[i], other.Xty[i]); err != nil {
+				return nil, err
+			}
+		}
+		result.Samples += other.Samples
+	}
+	return result, nil
+}
+
+// Function Train decrypts the provided aggregated Statistics with the
+// provided sdk.Client of the keyholder and returns the LinearModel that
+// solves the normal equations XᵀX * w = Xᵀy, with its parameters rounded to
+// the provided non negative number of decimals. If the Statistics do not
+// include the intercept, the intercept of the model is zero. It returns an
+// error if XᵀX is not a square matrix with the size of Xᵀy, if the
+// decryption fails or if XᵀX is singular.
+func Train(client *sdk.Client, stats *Statistics, precision int) (*LinearModel, error) {
+	if err := checkShape(stats); err != nil {
+		return nil, err
+	} else if len(stats.Xty) == 0 || (stats.Intercept && len(stats.Xty) < 2) {
+		return nil, errors.New("the statistics provided are empty")
+	} else if precision < 0 {
+		return nil, errors.New("the precision must not be negative")
+	}
+
+	var size = len(stats.Xty)
+	var xtx = make([][]*big.Rat, size)
+	var xty = make([]*big.Rat, size)
+	for i := 0; i < size; i++ {
+		xtx[i] = make([]*big.Rat, size)
+		for j := 0; j < size; j++ {
+			var err error
+			if xtx[i][j], err = decryptRat(client, stats.XtX[i][j]); err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		if xty[i], err = decryptRat(client, stats.Xty[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var solution, err = solve(xtx, xty)
+	if err != nil {
+		return nil, err
+	}
+
+	var params = make([]*number.Number, size)
+	for i, value := range solution {
+		if params[i], err = new(number.Number).SetString(value.FloatString(precision)); err != nil {
+			return nil, err
+		}
+	}
+
+	if stats.Intercept {
+		return NewLinearModel(params[1:], params[0], precision)
+	}
+	return NewLinearModel(params, new(number.Number).SetInt(0), precision)
+}
+
+// decryptRat decrypts the provided encrypted number.Number with the provided
+// sdk.Client and returns its exact value as big.Rat.
+func decryptRat(client *sdk.Client, encrypted *number.Number) (*big.Rat, error) {
+	var decrypted, err = client.Decrypt(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	var factor = new(big.Int).Exp(ten, new(big.Int).Abs(decrypted.Exp), nil)
+	if decrypted.Exp.Sign() >= 0 {
+		return new(big.Rat).SetInt(new(big.Int).Mul(decrypted.Value, factor)), nil
+	}
+	return new(big.Rat).SetFrac(decrypted.Value, factor), nil
+}
+
+// solve returns the exact solution of the linear system a * x = b using
+// Gauss-Jordan elimination with rational numbers. It returns an error if the
+// matrix a is singular.
+func solve(a [][]*big.Rat, b []*big.Rat) ([]*big.Rat, error) {
+	var size = len(b)
+	var m = make([][]*big.Rat, size)
+	for i := range m {
+		m[i] = make([]*big.Rat, size+1)
+		for j := 0; j < size; j++ {
+			m[i][j] = new(big.Rat).Set(a[i][j])
+		}
+		m[i][size] = new(big.Rat).Set(b[i])
+	}
+
+	for col := 0; col < size; col++ {
+		var pivot = -1
+		for row := col; row < size; row++ {
+			if m[row][col].Sign() != 0 {
+				pivot = row
+				break
+			}
+		}
+		if pivot < 0 {
+			return nil, errors.New("the system has no unique solution, XᵀX is singular")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+
+		for row := 0; row < size; row++ {
+			if row == col || m[row][col].Sign() == 0 {
+				continue
+			}
+
+			var factor = new(big.Rat).Quo(m[row][col], m[col][col])
+			for k := col; k <= size; k++ {
+				m[row][k].Sub(m[row][k], new(big.Rat).Mul(factor, m[col][k]))
+			}
+		}
+	}
+
+	var x = make([]*big.Rat, size)
+	for i := range x {
+		x[i] = new(big.Rat).Quo(m[i][size], m[i][i])
+	}
+	return x, nil
+}
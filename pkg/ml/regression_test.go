@@ -0,0 +1,164 @@
+package ml
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+)
+
+// dataset returns the features and targets of a noisy linear dataset with the
+// provided offset, y = 1.5 + 2*x1 - 0.5*x2 + noise.
+func dataset(offset, size int) ([][]float64, []float64) {
+	var features = make([][]float64, size)
+	var targets = make([]float64, size)
+	for i := 0; i < size; i++ {
+		var x1, x2 = float64(offset+i) * 0.5, float64((offset+i)%7) - 3
+		var noise = float64((offset+i)%3-1) * 0.1
+		features[i] = []float64{x1, x2}
+		targets[i] = 1.5 + 2*x1 - 0.5*x2 + noise
+	}
+	return features, targets
+}
+
+func toNumbers(t *testing.T, features [][]float64, targets []float64) ([][]*number.Number, []*number.Number) {
+	var x = make([][]*number.Number, len(features))
+	var y = make([]*number.Number, len(targets))
+	for i := range features {
+		x[i] = make([]*number.Number, len(features[i]))
+		for j, value := range features[i] {
+			x[i][j], _ = new(number.Number).SetFloat(value)
+		}
+		y[i], _ = new(number.Number).SetFloat(targets[i])
+	}
+	return x, y
+}
+
+// plainSolver fits the linear regression with intercept over the plain
+// dataset using float64 Gaussian elimination over the normal equations.
+func plainSolver(features [][]float64, targets []float64) []float64 {
+	var size = len(features[0]) + 1
+	var m = make([][]float64, size)
+	for i := range m {
+		m[i] = make([]float64, size+1)
+	}
+	for s, sample := range features {
+		var row = append([]float64{1}, sample...)
+		for i := 0; i < size; i++ {
+			for j := 0; j < size; j++ {
+				m[i][j] += row[i] * row[j]
+			}
+			m[i][size] += row[i] * targets[s]
+		}
+	}
+
+	for col := 0; col < size; col++ {
+		for row := 0; row < size; row++ {
+			if row != col {
+				var factor = m[row][col] / m[col][col]
+				for k := col; k <= size; k++ {
+					m[row][k] -= factor * m[col][k]
+				}
+			}
+		}
+	}
+
+	var result = make([]float64, size)
+	for i := range result {
+		result[i] = m[i][size] / m[i][i]
+	}
+	return result
+}
+
+func TestTrain(t *testing.T) {
+	var allFeatures [][]float64
+	var allTargets []float64
+	var parties []*Statistics
+	for party, size := range []int{8, 5, 12} {
+		var features, targets = dataset(party*20, size)
+		allFeatures = append(allFeatures, features...)
+		allTargets = append(allTargets, targets...)
+
+		var x, y = toNumbers(t, features, targets)
+		var stats, err = EncryptStatistics(client.PubKey, x, y, true)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+		parties = append(parties, stats)
+	}
+
+	var aggregated, err = AggregateStatistics(client.PubKey, parties...)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if aggregated.Samples != 25 {
+		t.Fatalf("expected 25, got %d", aggregated.Samples)
+	}
+
+	var model *LinearModel
+	if model, err = Train(client, aggregated, 6); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var expected = plainSolver(allFeatures, allTargets)
+	var params = []*number.Number{model.Intercept, model.Weights[0], model.Weights[1]}
+	for i, param := range params {
+		if math.Abs(param.Float()-expected[i]) > 1e-5 {
+			t.Fatalf("param %d: expected %f, got %f", i, expected[i], param.Float())
+		}
+	}
+	if fmt.Sprintf("%.1f %.1f %.1f", params[0].Float(), params[1].Float(), params[2].Float()) != "1.5 2.0 -0.5" {
+		t.Fatalf("unexpected model %s %s %s", params[0], params[1], params[2])
+	}
+}
+
+func TestTrainWithoutIntercept(t *testing.T) {
+	var x, y = toNumbers(t, [][]float64{{1}, {2}, {3}}, []float64{2, 4, 6})
+	var stats, _ = EncryptStatistics(client.PubKey, x, y, false)
+	var model, err = Train(client, stats, 3)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if model.Weights[0].String() != "2" || model.Intercept.String() != "0" {
+		t.Fatalf("expected 2 and 0, got %s and %s", model.Weights[0], model.Intercept)
+	}
+}
+
+func TestTrainErrors(t *testing.T) {
+	var x, y = toNumbers(t, [][]float64{{1, 2}, {2, 4}, {3, 6}}, []float64{1, 2, 3})
+	if _, err := EncryptStatistics(client.PubKey, nil, nil, true); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = EncryptStatistics(client.PubKey, x, y[:2], true); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// collinear features produce a singular XᵀX
+	var stats, _ = EncryptStatistics(client.PubKey, x, y, true)
+	if _, err := Train(client, stats, 3); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var other, _ = EncryptStatistics(client.PubKey, x, y, false)
+	if _, err := AggregateStatistics(client.PubKey, stats, other); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = AggregateStatistics(client.PubKey); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// XᵀX must be a square matrix with the size of Xᵀy
+	var malformed = []*Statistics{
+		nil,
+		{XtX: stats.XtX[:2], Xty: stats.Xty, Intercept: true},
+		{XtX: [][]*number.Number{stats.XtX[0], stats.XtX[1][:1], stats.XtX[2]}, Xty: stats.Xty, Intercept: true},
+		{XtX: [][]*number.Number{stats.XtX[0], {nil, nil, nil}, stats.XtX[2]}, Xty: stats.Xty, Intercept: true},
+		{XtX: stats.XtX, Xty: []*number.Number{stats.Xty[0], nil, stats.Xty[2]}, Intercept: true},
+	}
+	for i, bad := range malformed {
+		if _, err := Train(client, bad, 3); err == nil {
+			t.Fatalf("%d: expected error, got nil", i)
+		} else if _, err = AggregateStatistics(client.PubKey, bad); err == nil {
+			t.Fatalf("%d: expected error, got nil", i)
+		} else if _, err = AggregateStatistics(client.PubKey, stats, bad); err == nil {
+			t.Fatalf("%d: expected error, got nil", i)
+		}
+	}
+}
@@ -9,20 +9,46 @@
 // numbers too.
 package number
 
-import "math/big"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
 
 var iZero = big.NewInt(0)
 var iOne = big.NewInt(1)
 var iTen = big.NewInt(10)
-var fZero = big.NewFloat(0)
 var fTen = big.NewFloat(10)
 
+// MaxExp is the maximum absolute exponent accepted when a Number is parsed from
+// a string or decoded from JSON. It bounds the cost of the operations that
+// scale the values by a power of ten, such as the exponents alignment or the
+// decimal representation of a Number.
+const MaxExp = 4096
+
+var bMaxExp = big.NewInt(MaxExp)
+
+// checkExp returns an error if the absolute value of the provided exponent is
+// greater than MaxExp.
+func checkExp(exp *big.Int) error {
+	if exp.CmpAbs(bMaxExp) > 0 {
+		return fmt.Errorf("exponent %s out of range [-%d, %d]", exp, MaxExp, MaxExp)
+	}
+	return nil
+}
+
 // Struct Number includes the integers value of the original number with the
 // original power of ten exponent, allowing to encrypt and decrypt the value and
-// operate over it.
+// operate over it. Encrypted numbers could also include the identifier of the
+// key used to encrypt them (KeyID), to select the right key to decrypt them.
 type Number struct {
 	Value     *big.Int
 	Exp       *big.Int
+	KeyID     string
 	encrypted bool
 }
 
@@ -39,6 +65,7 @@ func (num *Number) IsEncrypted() bool {
 func (num *Number) Set(original *Number) *Number {
 	num.Value = original.Value
 	num.Exp = original.Exp
+	num.KeyID = original.KeyID
 	num.encrypted = false
 
 	return num
@@ -53,6 +80,42 @@ func (num *Number) SetEncrypted(original *Number) *Number {
 	return num
 }
 
+// jsonNumber is the JSON representation of a Number, including its encrypted
+// flag.
+type jsonNumber struct {
+	Value     *big.Int `json:"value"`
+	Exp       *big.Int `json:"exp"`
+	Encrypted bool     `json:"encrypted"`
+	KeyID     string   `json:"keyId,omitempty"`
+}
+
+// Function MarshalJSON implements the json.Marshaler interface, encoding the
+// value, the exponent and the encrypted flag of the current Number num.
+func (num *Number) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNumber{num.Value, num.Exp, num.encrypted, num.KeyID})
+}
+
+// Function UnmarshalJSON implements the json.Unmarshaler interface, decoding
+// the value, the exponent and the encrypted flag into the current Number num.
+// It returns an error if the value or the exponent are missing, or if the
+// exponent is out of the range defined by MaxExp.
+func (num *Number) UnmarshalJSON(data []byte) error {
+	var raw jsonNumber
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	} else if raw.Value == nil || raw.Exp == nil {
+		return errors.New("value and exponent are required")
+	} else if err := checkExp(raw.Exp); err != nil {
+		return err
+	}
+
+	num.Value = raw.Value
+	num.Exp = raw.Exp
+	num.encrypted = raw.Encrypted
+	num.KeyID = raw.KeyID
+	return nil
+}
+
 // Function SetInt compute and stores into the current Number num the correct
 // integer value and exponent of the provided int input and return it as result.
 func (num *Number) SetInt(input int64) *Number {
@@ -76,24 +139,123 @@ func (num *Number) SetInt(input int64) *Number {
 
 // Function SetFloat compute and stores into the current Number num the correct
 // integer value and exponent of the provided float input and return it as
-// result.
-func (num *Number) SetFloat(input float64) *Number {
-	var bInput = big.NewFloat(input)
-	var value, exp int64 = int64(input), 0
+// result. The conversion is exact: it uses the shortest decimal representation
+// of the input that parses back to the same float64 (see strconv.FormatFloat),
+// so values like 0.1, 1e-30 or 1e300 are encoded without any precision loss.
+// It returns an error if the provided input is NaN or an infinite value.
+func (num *Number) SetFloat(input float64) (*Number, error) {
+	if math.IsNaN(input) {
+		return nil, errors.New("NaN cannot be represented as a Number")
+	} else if math.IsInf(input, 0) {
+		return nil, errors.New("infinite values cannot be represented as a Number")
+	} else if input == 0 {
+		return num.SetInt(0), nil
+	}
 
-	var diff = new(big.Float).Sub(bInput, big.NewFloat(float64(value)))
-	for diff.Cmp(fZero) != 0 {
-		bInput = new(big.Float).Mul(bInput, fTen)
-		value, _ = bInput.Int64()
-		exp--
+	// Get the shortest scientific notation of the input, with the form
+	// '[-]d.ddddde±dd', split it into its mantissa digits and its exponent and
+	// move the decimal point of the mantissa to the exponent, where:
+	//		d.dddd * 10^e => dddd * 10^(e - len(dddd) + 1)
+	var text = strconv.FormatFloat(input, 'e', -1, 64)
+	var mantissa, rawExp, _ = strings.Cut(text, "e")
+	var exp, err = strconv.ParseInt(rawExp, 10, 64)
+	if err != nil {
+		return nil, err
+	}
 
-		diff = new(big.Float).Sub(bInput, big.NewFloat(float64(value)))
+	var digits = strings.Replace(mantissa, ".", "", 1)
+	var value, ok = new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, errors.New("error parsing float mantissa")
 	}
+	exp -= int64(len(strings.TrimPrefix(digits, "-")) - 1)
 
-	var numInt = new(Number).SetInt(value)
-	num.Value = numInt.Value
-	num.Exp = new(big.Int).Add(numInt.Exp, big.NewInt(exp))
-	return num
+	num.Value = value
+	num.Exp = big.NewInt(exp)
+	num.encrypted = false
+	return num.Normalize()
+}
+
+// Function SetString compute and stores into the current Number num the exact
+// integer value and exponent of the decimal number provided as string and
+// return it as result. The input could include a sign, a decimal point and an
+// exponent in scientific notation, for example "-12.05" or "1.5e-10". It
+// returns an error if the input is not a valid decimal number or if the
+// exponent of the resulting Number is out of the range defined by MaxExp.
+func (num *Number) SetString(input string) (*Number, error) {
+	var mantissa, rawExp = input, "0"
+	if i := strings.IndexAny(input, "eE"); i >= 0 {
+		mantissa, rawExp = input[:i], input[i+1:]
+	}
+
+	var exp, ok = new(big.Int).SetString(rawExp, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid exponent in '%s'", input)
+	} else if exp.CmpAbs(new(big.Int).Lsh(bMaxExp, 1)) > 0 {
+		// Reject early the exponents that can not be brought into the range,
+		// avoiding to operate with huge exponents.
+		return nil, checkExp(exp)
+	}
+
+	var negative = strings.HasPrefix(mantissa, "-")
+	if negative || strings.HasPrefix(mantissa, "+") {
+		mantissa = mantissa[1:]
+	}
+
+	var integer, fraction, _ = strings.Cut(mantissa, ".")
+	var digits = integer + fraction
+	if digits == "" || strings.ContainsAny(digits, "+-") {
+		return nil, fmt.Errorf("invalid number '%s'", input)
+	}
+
+	var value *big.Int
+	if value, ok = new(big.Int).SetString(digits, 10); !ok {
+		return nil, fmt.Errorf("invalid number '%s'", input)
+	} else if negative {
+		value.Neg(value)
+	}
+
+	var result = &Number{Value: value, Exp: exp.Sub(exp, big.NewInt(int64(len(fraction))))}
+	if _, err := result.Normalize(); err != nil {
+		return nil, err
+	} else if err = checkExp(result.Exp); err != nil {
+		return nil, err
+	}
+	return num.Set(result), nil
+}
+
+// Function String returns the exact decimal representation of the current
+// Number num, computing the value of num.Value * 10^num.Exp without losing
+// precision. It is only meaningful for not encrypted Numbers. If the exponent
+// is out of the range defined by MaxExp, the Number is represented in
+// scientific notation, for example "12e-5000", to avoid building huge
+// strings.
+func (num *Number) String() string {
+	if num.Value == nil || num.Exp == nil {
+		return "<nil>"
+	} else if checkExp(num.Exp) != nil {
+		return num.Value.String() + "e" + num.Exp.String()
+	}
+
+	var sign, digits = "", num.Value.String()
+	if num.Value.Sign() < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	if num.Exp.Sign() >= 0 {
+		if num.Value.Sign() == 0 {
+			return "0"
+		}
+		return sign + digits + strings.Repeat("0", int(num.Exp.Int64()))
+	}
+
+	var decimals = int(-num.Exp.Int64())
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	var point = len(digits) - decimals
+	return sign + digits[:point] + "." + digits[point:]
 }
 
 // Function Int returns the original int value of the current Number num
@@ -135,3 +297,175 @@ func (num *Number) Float() (output float64) {
 	output, _ = bOutput.Float64()
 	return
 }
+
+// checkPlain returns an error if any of the provided Numbers is encrypted,
+// because the plaintext arithmetic cannot be performed over ciphertexts.
+func checkPlain(inputs ...*Number) error {
+	for _, input := range inputs {
+		if input.IsEncrypted() {
+			return errors.New("plaintext operations cannot be performed over encrypted Numbers")
+		}
+	}
+
+	return nil
+}
+
+// align returns the values of both Numbers provided scaled to share the same
+// exponent, which is the lowest of both, and the exponent itself:
+//
+//	a = 12 * 10^2, b = 5 * 10^-1 --> 12000 * 10^-1, 5 * 10^-1
+func align(a, b *Number) (*big.Int, *big.Int, *big.Int) {
+	var aValue, bValue = new(big.Int).Set(a.Value), new(big.Int).Set(b.Value)
+	var cmp = a.Exp.Cmp(b.Exp)
+	if cmp == 0 {
+		return aValue, bValue, new(big.Int).Set(a.Exp)
+	}
+
+	var expDiff = new(big.Int).Abs(new(big.Int).Sub(a.Exp, b.Exp))
+	var factor = new(big.Int).Exp(iTen, expDiff, nil)
+	if cmp > 0 {
+		return aValue.Mul(aValue, factor), bValue, new(big.Int).Set(b.Exp)
+	}
+	return aValue, bValue.Mul(bValue, factor), new(big.Int).Set(a.Exp)
+}
+
+// Function Add stores into the current Number num the exact addition of the
+// plain Numbers a and b, and return it as result. Both inputs are scaled to
+// the lowest exponent before adding their values. It returns an error if any
+// of the inputs is encrypted.
+func (num *Number) Add(a, b *Number) (*Number, error) {
+	if err := checkPlain(a, b); err != nil {
+		return nil, err
+	}
+
+	var aValue, bValue, exp = align(a, b)
+	num.Value = aValue.Add(aValue, bValue)
+	num.Exp = exp
+	num.encrypted = false
+	return num, nil
+}
+
+// Function Mul stores into the current Number num the exact multiplication of
+// the plain Numbers a and b, and return it as result. The resulting value is
+// the product of both values and its exponent the addition of both exponents.
+// It returns an error if any of the inputs is encrypted.
+func (num *Number) Mul(a, b *Number) (*Number, error) {
+	if err := checkPlain(a, b); err != nil {
+		return nil, err
+	}
+
+	num.Value = new(big.Int).Mul(a.Value, b.Value)
+	num.Exp = new(big.Int).Add(a.Exp, b.Exp)
+	num.encrypted = false
+	return num, nil
+}
+
+// Function Quo stores into the current Number num the division of the plain
+// Numbers a and b rounded half away from zero to the provided number of
+// decimals, and return it normalized as result. The division is exact when the
+// quotient has no more decimals than the provided ones:
+//
+//	Quo(3, 3, 16) --> 1, Quo(1, 8, 16) --> 0.125, Quo(2, 3, 4) --> 0.6667
+//
+// It returns an error if any of the inputs is encrypted, if b is zero or if
+// the number of decimals is negative.
+func (num *Number) Quo(a, b *Number, decimals int) (*Number, error) {
+	if err := checkPlain(a, b); err != nil {
+		return nil, err
+	} else if b.Value.Sign() == 0 {
+		return nil, errors.New("division by zero")
+	} else if decimals < 0 {
+		return nil, errors.New("the number of decimals must not be negative")
+	}
+
+	// Compute a.Value * 10^(a.Exp - b.Exp + decimals) / b.Value, that is the
+	// value of the quotient with exponent -decimals.
+	var numerator, denominator = new(big.Int).Set(a.Value), new(big.Int).Set(b.Value)
+	var shift = new(big.Int).Sub(a.Exp, b.Exp)
+	shift.Add(shift, big.NewInt(int64(decimals)))
+	var factor = new(big.Int).Exp(iTen, new(big.Int).Abs(shift), nil)
+	if shift.Sign() >= 0 {
+		numerator.Mul(numerator, factor)
+	} else {
+		denominator.Mul(denominator, factor)
+	}
+
+	if denominator.Sign() < 0 {
+		numerator.Neg(numerator)
+		denominator.Neg(denominator)
+	}
+
+	var quo, rem = new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if rem.Lsh(rem.Abs(rem), 1).Cmp(denominator) >= 0 {
+		if numerator.Sign() < 0 {
+			quo.Sub(quo, iOne)
+		} else {
+			quo.Add(quo, iOne)
+		}
+	}
+
+	num.Value = quo
+	num.Exp = big.NewInt(-int64(decimals))
+	num.encrypted = false
+	return num.Normalize()
+}
+
+// Function Neg stores into the current Number num the negated value of the
+// plain Number a, and return it as result. It returns an error if the input
+// is encrypted.
+func (num *Number) Neg(a *Number) (*Number, error) {
+	if err := checkPlain(a); err != nil {
+		return nil, err
+	}
+
+	num.Value = new(big.Int).Neg(a.Value)
+	num.Exp = new(big.Int).Set(a.Exp)
+	num.encrypted = false
+	return num, nil
+}
+
+// Function Cmp compares the current plain Number num with the plain Number b
+// provided and returns -1 if num < b, 0 if num == b or +1 if num > b. The
+// comparison is exact, independently of the exponents of both Numbers. It
+// returns an error if any of the Numbers is encrypted.
+func (num *Number) Cmp(b *Number) (int, error) {
+	if err := checkPlain(num, b); err != nil {
+		return 0, err
+	}
+
+	var numValue, bValue, _ = align(num, b)
+	return numValue.Cmp(bValue), nil
+}
+
+// Function Normalize moves the trailing zeros of the value of the current plain
+// Number num into its exponent and return it as result, obtaining the
+// shortest representation of the same number:
+//
+//	12000 * 10^-1 --> 12 * 10^2
+//
+// The zero value is normalized to 0 * 10^1, as number.SetInt does. It returns
+// an error if the current Number is encrypted.
+func (num *Number) Normalize() (*Number, error) {
+	if err := checkPlain(num); err != nil {
+		return nil, err
+	}
+
+	if num.Value.Sign() == 0 {
+		return num.SetInt(0), nil
+	}
+
+	var value, exp = new(big.Int).Set(num.Value), new(big.Int).Set(num.Exp)
+	var quo, mod = new(big.Int), new(big.Int)
+	for {
+		if quo.QuoRem(value, iTen, mod); mod.Sign() != 0 {
+			break
+		}
+
+		value.Set(quo)
+		exp.Add(exp, iOne)
+	}
+
+	num.Value = value
+	num.Exp = exp
+	return num, nil
+}
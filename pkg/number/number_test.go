@@ -1,15 +1,20 @@
 package number
 
 import (
+	"encoding/json"
+	"math"
 	"math/big"
+	"math/rand"
+	"strconv"
 	"testing"
+	"testing/quick"
 )
 
 func TestSet(t *testing.T) {
 	var value = big.NewInt(123)
 	var exp = big.NewInt(3)
 
-	var expected = &Number{value, exp, false}
+	var expected = &Number{value, exp, "", false}
 	var result = new(Number).Set(expected)
 	if expected.Value.Cmp(result.Value) != 0 {
 		t.Fatalf("expected %d, got %d", expected.Value, result.Value)
@@ -28,7 +33,7 @@ func TestSetEncrypted(t *testing.T) {
 
 	var value = big.NewInt(123)
 	var exp = big.NewInt(3)
-	var input = &Number{value, exp, false}
+	var input = &Number{value, exp, "", false}
 	num.SetEncrypted(input)
 	if num.encrypted != true || num.IsEncrypted() != true {
 		t.Fatalf("expected true, got %t", num.encrypted)
@@ -76,9 +81,9 @@ func TestSetFloat(t *testing.T) {
 	var bValue, bExp *big.Int = big.NewInt(1240036), big.NewInt(-2)
 	var cValue, cExp *big.Int = big.NewInt(0), big.NewInt(1)
 
-	var resA = new(Number).SetFloat(A)
-	var resB = new(Number).SetFloat(B)
-	var resC = new(Number).SetFloat(C)
+	var resA, _ = new(Number).SetFloat(A)
+	var resB, _ = new(Number).SetFloat(B)
+	var resC, _ = new(Number).SetFloat(C)
 
 	if resA.Value.Cmp(aValue) != 0 {
 		t.Fatalf("expected %d, got %d", aValue, resA.Value)
@@ -105,6 +110,66 @@ func TestSetFloat(t *testing.T) {
 	}
 }
 
+func TestSetFloatEdgeCases(t *testing.T) {
+	var inputs = []float64{0.1, -0.3, 1e-30, 1e300, -1e300, math.MaxFloat64,
+		math.SmallestNonzeroFloat64, 123456789.123456789}
+	var values = []int64{1, -3, 1, 1, -1, 17976931348623157, 5, 12345678912345679}
+	var exps = []int64{-1, -1, -30, 300, 300, 292, -324, -8}
+
+	for i, input := range inputs {
+		var res, err = new(Number).SetFloat(input)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if res.Value.Cmp(big.NewInt(values[i])) != 0 {
+			t.Fatalf("expected %d, got %d", values[i], res.Value)
+		} else if res.Exp.Cmp(big.NewInt(exps[i])) != 0 {
+			t.Fatalf("expected %d, got %d", exps[i], res.Exp)
+		}
+	}
+
+	for _, input := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := new(Number).SetFloat(input); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	}
+}
+
+// checkSetFloat returns if the Number generated from the input provided
+// represents exactly the shortest decimal version of it, without trailing
+// zeros into its value.
+func checkSetFloat(input float64) bool {
+	var res, err = new(Number).SetFloat(input)
+	if err != nil {
+		return false
+	} else if input == 0 {
+		return res.Value.Sign() == 0
+	} else if new(big.Int).Rem(res.Value, iTen).Sign() == 0 {
+		return false
+	}
+
+	var text = res.Value.String() + "e" + res.Exp.String()
+	var parsed, _ = strconv.ParseFloat(text, 64)
+	return parsed == input
+}
+
+func TestSetFloatProperties(t *testing.T) {
+	if err := quick.Check(checkSetFloat, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Fatal(err)
+	}
+
+	var random = rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		var input = math.Float64frombits(random.Uint64())
+		if math.IsNaN(input) || math.IsInf(input, 0) {
+			continue
+		}
+
+		if !checkSetFloat(input) {
+			t.Fatalf("wrong encoding of %v", input)
+		}
+	}
+}
+
 func TestInt(t *testing.T) {
 	var A, B int64 = 12, -12400
 
@@ -122,8 +187,9 @@ func TestInt(t *testing.T) {
 	var C, D float64 = 0.125, -12400.36
 	var expC, expD int64 = 0, -12401
 
-	var resC = new(Number).SetFloat(C).Int()
-	var resD = new(Number).SetFloat(D).Int()
+	var numC, _ = new(Number).SetFloat(C)
+	var numD, _ = new(Number).SetFloat(D)
+	var resC, resD = numC.Int(), numD.Int()
 
 	if expC != resC {
 		t.Fatalf("expected %d, got %d", expC, resC)
@@ -151,8 +217,9 @@ func TestFloat(t *testing.T) {
 
 	var C, D float64 = 0.125, -12400.36
 
-	var resC = new(Number).SetFloat(C).Float()
-	var resD = new(Number).SetFloat(D).Float()
+	var numC, _ = new(Number).SetFloat(C)
+	var numD, _ = new(Number).SetFloat(D)
+	var resC, resD = numC.Float(), numD.Float()
 
 	if C != resC {
 		t.Fatalf("expected %.5f, got %.5f", C, resC)
@@ -162,3 +229,244 @@ func TestFloat(t *testing.T) {
 		t.Fatalf("expected %.5f, got %.5f", D, resD)
 	}
 }
+
+func TestAdd(t *testing.T) {
+	var a, _ = new(Number).SetFloat(12.5)
+	var b = new(Number).SetInt(-1200)
+	var expected, _ = new(Number).SetFloat(-1187.5)
+
+	var res, err = new(Number).Add(a, b)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if cmp, _ := res.Cmp(expected); cmp != 0 {
+		t.Fatalf("expected %v, got %v", expected.Float(), res.Float())
+	}
+
+	// check that the inputs are not modified and can be used as receiver
+	if a.Float() != 12.5 || b.Int() != -1200 {
+		t.Fatalf("inputs modified: %v, %v", a.Float(), b.Int())
+	} else if res, _ = a.Add(a, a); res.Float() != 25 {
+		t.Fatalf("expected 25, got %v", res.Float())
+	}
+
+	var encrypted = new(Number).SetEncrypted(b)
+	if _, err = new(Number).Add(a, encrypted); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestMul(t *testing.T) {
+	var a, _ = new(Number).SetFloat(0.1)
+	var b, _ = new(Number).SetFloat(-0.2)
+	var expected = &Number{big.NewInt(-2), big.NewInt(-2), "", false}
+
+	var res, err = new(Number).Mul(a, b)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if res.Value.Cmp(expected.Value) != 0 || res.Exp.Cmp(expected.Exp) != 0 {
+		t.Fatalf("expected %d * 10^%d, got %d * 10^%d", expected.Value,
+			expected.Exp, res.Value, res.Exp)
+	}
+
+	var encrypted = new(Number).SetEncrypted(b)
+	if _, err = new(Number).Mul(encrypted, a); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestQuo(t *testing.T) {
+	var tests = []struct {
+		a, b     string
+		decimals int
+		expected string
+	}{
+		{"3", "3", 16, "1"},
+		{"1", "8", 16, "0.125"},
+		{"2", "3", 4, "0.6667"},
+		{"-2", "3", 4, "-0.6667"},
+		{"2", "-3", 0, "-1"},
+		{"1.5e3", "0.25", 0, "6000"},
+		{"0", "7", 2, "0"},
+		{"1", "3", 0, "0"},
+		{"15", "10", 0, "2"},
+		{"-15", "10", 0, "-2"},
+	}
+
+	for _, test := range tests {
+		var a, _ = new(Number).SetString(test.a)
+		var b, _ = new(Number).SetString(test.b)
+		var res, err = new(Number).Quo(a, b, test.decimals)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if res.String() != test.expected {
+			t.Fatalf("%s / %s: expected %s, got %s", test.a, test.b, test.expected, res)
+		}
+	}
+
+	var a = new(Number).SetInt(1)
+	if _, err := new(Number).Quo(a, new(Number).SetInt(0), 2); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = new(Number).Quo(a, a, -1); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = new(Number).Quo(new(Number).SetEncrypted(a), a, 2); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestNeg(t *testing.T) {
+	var a, _ = new(Number).SetFloat(-3.75)
+	if res, err := new(Number).Neg(a); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if res.Float() != 3.75 {
+		t.Fatalf("expected 3.75, got %v", res.Float())
+	} else if a.Float() != -3.75 {
+		t.Fatalf("expected -3.75, got %v", a.Float())
+	}
+
+	var encrypted = new(Number).SetEncrypted(a)
+	if _, err := new(Number).Neg(encrypted); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestCmp(t *testing.T) {
+	var a = &Number{big.NewInt(12000), big.NewInt(-1), "", false}
+	var b = &Number{big.NewInt(12), big.NewInt(2), "", false}
+	var c, _ = new(Number).SetFloat(1200.5)
+	var d, _ = new(Number).SetFloat(-1200.5)
+
+	var pairs = [][2]*Number{{a, b}, {a, c}, {c, a}, {d, a}, {d, d}}
+	var expected = []int{0, -1, 1, -1, 0}
+	for i, pair := range pairs {
+		if res, err := pair[0].Cmp(pair[1]); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if res != expected[i] {
+			t.Fatalf("expected %d, got %d", expected[i], res)
+		}
+	}
+
+	var encrypted = new(Number).SetEncrypted(a)
+	if _, err := encrypted.Cmp(b); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = b.Cmp(encrypted); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	var inputs = []*Number{
+		{big.NewInt(12000), big.NewInt(-1), "", false},
+		{big.NewInt(-505), big.NewInt(3), "", false},
+		{big.NewInt(0), big.NewInt(-4), "", false},
+	}
+	var values = []int64{12, -505, 0}
+	var exps = []int64{2, 3, 1}
+
+	for i, input := range inputs {
+		if res, err := input.Normalize(); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if res.Value.Cmp(big.NewInt(values[i])) != 0 {
+			t.Fatalf("expected %d, got %d", values[i], res.Value)
+		} else if res.Exp.Cmp(big.NewInt(exps[i])) != 0 {
+			t.Fatalf("expected %d, got %d", exps[i], res.Exp)
+		}
+	}
+
+	var encrypted = new(Number).SetEncrypted(inputs[0])
+	if _, err := encrypted.Normalize(); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var plain, _ = new(Number).SetFloat(-12.05)
+	var encrypted = new(Number).SetEncrypted(&Number{big.NewInt(123456789), big.NewInt(-3), "key", false})
+
+	for _, input := range []*Number{plain, encrypted} {
+		var data, err = json.Marshal(input)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		var result = new(Number)
+		if err = json.Unmarshal(data, result); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if result.Value.Cmp(input.Value) != 0 || result.Exp.Cmp(input.Exp) != 0 {
+			t.Fatalf("expected %d * 10^%d, got %d * 10^%d", input.Value, input.Exp,
+				result.Value, result.Exp)
+		} else if result.IsEncrypted() != input.IsEncrypted() {
+			t.Fatalf("expected %t, got %t", input.IsEncrypted(), result.IsEncrypted())
+		} else if result.KeyID != input.KeyID {
+			t.Fatalf("expected %s, got %s", input.KeyID, result.KeyID)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"value": 12}`), new(Number)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if err = json.Unmarshal([]byte(`{"value": "a", "exp": 1}`), new(Number)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if err = json.Unmarshal([]byte(`{"value": 1, "exp": -1000000000}`), new(Number)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestSetString(t *testing.T) {
+	var inputs = []string{"12", "-12400", "0", "-0.0", "12400.36", "+0.125",
+		"1.5e-10", "-3E2", "123456789012345678901234567890.000000000000000000001", ".5"}
+	var values = []string{"12", "-124", "0", "0", "1240036", "125", "15", "-3",
+		"123456789012345678901234567890000000000000000000001", "5"}
+	var exps = []int64{0, 2, 1, 1, -2, -3, -11, 2, -21, -1}
+
+	for i, input := range inputs {
+		var res, err = new(Number).SetString(input)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if res.Value.String() != values[i] {
+			t.Fatalf("expected %s, got %d", values[i], res.Value)
+		} else if res.Exp.Int64() != exps[i] {
+			t.Fatalf("expected %d, got %d", exps[i], res.Exp)
+		}
+	}
+
+	var bounds = []string{"1e4096", "-1e-4096", "1000e4093"}
+	for _, input := range bounds {
+		if _, err := new(Number).SetString(input); err != nil {
+			t.Fatalf("expected nil for '%s', got %s", input, err)
+		}
+	}
+
+	for _, input := range []string{"", "-", "abc", "1.2.3", "1e", "1e1.5", "--1", "1-2", "e5",
+		"1e4097", "0.1e-4096", "1e-9223372036854775809", "1e99999999999999999999999"} {
+		if _, err := new(Number).SetString(input); err == nil {
+			t.Fatalf("expected error for '%s', got nil", input)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	var inputs = []*Number{
+		{big.NewInt(12), big.NewInt(0), "", false},
+		{big.NewInt(-124), big.NewInt(2), "", false},
+		{big.NewInt(0), big.NewInt(1), "", false},
+		{big.NewInt(1240036), big.NewInt(-2), "", false},
+		{big.NewInt(-125), big.NewInt(-3), "", false},
+		{big.NewInt(15), big.NewInt(-11), "", false},
+	}
+	var expected = []string{"12", "-12400", "0", "12400.36", "-0.125", "0.00000000015"}
+
+	for i, input := range inputs {
+		if result := input.String(); result != expected[i] {
+			t.Fatalf("expected %s, got %s", expected[i], result)
+		}
+
+		var parsed, _ = new(Number).SetString(input.String())
+		if cmp, _ := parsed.Cmp(input); cmp != 0 {
+			t.Fatalf("expected %s, got %s", input, parsed)
+		}
+	}
+
+	var huge = &Number{big.NewInt(12), big.NewInt(-5000), "", false}
+	if result := huge.String(); result != "12e-5000" {
+		t.Fatalf("expected 12e-5000, got %s", result)
+	}
+}
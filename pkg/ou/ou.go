@@ -0,0 +1,205 @@
+// Package ou is the Go implementation of the Okamoto–Uchiyama Cryptosystem
+// described by Tatsuaki Okamoto and Shigenori Uchiyama in 1998. It is a
+// probabilistic asymmetric algorithm for public key cryptography, that support
+// homomorphic addition and multiplication of plaintexts to a ciphertext, like
+// Paillier, but its ciphertexts are computed modulo n instead of n^2, so they
+// are smaller for an equivalent key size.
+// The current package supports same homomorphic operation over non-positive
+// integers mapping them into the plaintext space, as the paillier package does.
+// Read more: https://en.wikipedia.org/wiki/Okamoto%E2%80%93Uchiyama_cryptosystem
+package ou
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/internal/prime"
+)
+
+var bOne *big.Int = new(big.Int).SetInt64(1)
+var bTwo *big.Int = new(big.Int).SetInt64(2)
+
+// Struct PublicKey includes the required parameters n, g and h, and the length
+// of the key, which is the length of the prime factors of n.
+type PublicKey struct {
+	N, G, H *big.Int
+	Len     int64
+}
+
+// Struct PrivateKey includes the required parameter p, the precomputed values
+// p^2 (psq) and L(g^(p-1) mod p^2)^-1 mod p (u), with the associated PublicKey
+// and the length of the key.
+type PrivateKey struct {
+	p, psq, u *big.Int
+	Len       int64
+	PubKey    *PublicKey
+}
+
+// Function NewKeys computes the required parameters of a ou.PrivateKey,
+// including the parameters of its ou.PublicKey, following the key generation
+// algorithm. Read more:
+// https://en.wikipedia.org/wiki/Okamoto%E2%80%93Uchiyama_cryptosystem#Key_generation
+func NewKeys(size int) (*PrivateKey, error) {
+	return NewKeysContext(context.Background(), size)
+}
+
+// Function NewKeysContext works as NewKeys but checks the provided context
+// during the generation of the prime numbers, returning ctx.Err() if it is
+// cancelled before the key generation finishes.
+func NewKeysContext(ctx context.Context, size int) (*PrivateKey, error) {
+	var err error
+	if size < 16 {
+		return nil, errors.New("size must be greater than 16")
+	}
+
+	// Calc p and q large prime numbers with equivalent length
+	var p, q *big.Int
+	if p, err = prime.Generate(ctx, size); err != nil {
+		return nil, err
+	} else if q, err = prime.Generate(ctx, size); err != nil {
+		return nil, err
+	}
+
+	// Compute public key parameters n (n), g (g) and h (h), where:
+	//		n = p^2 * q => n
+	//		g ∈ Z*n, where g^(p-1) mod p^2 != 1 => g
+	//		h = g^n mod n => h
+	// Also compute private key parameters p^2 (psq) and u (u), where:
+	//		L(x) = (x - 1) / p
+	//		u = L(g^(p-1) mod p^2)^-1 mod p => u
+	var (
+		pl  = new(big.Int).Sub(p, bOne)
+		psq = new(big.Int).Mul(p, p)
+		n   = new(big.Int).Mul(psq, q)
+		g   *big.Int
+		gp  *big.Int
+	)
+	for {
+		if g, err = rand.Int(rand.Reader, n); err != nil {
+			return nil, err
+		} else if g.Cmp(bTwo) < 0 || new(big.Int).GCD(nil, nil, g, n).Cmp(bOne) != 0 {
+			continue
+		}
+
+		if gp = new(big.Int).Exp(g, pl, psq); gp.Cmp(bOne) != 0 {
+			break
+		}
+	}
+
+	var (
+		h = new(big.Int).Exp(g, n, n)
+		l = new(big.Int).Div(new(big.Int).Sub(gp, bOne), p)
+		u = new(big.Int).ModInverse(l, p)
+	)
+	if u == nil {
+		return nil, errors.New("error computing the private key parameters")
+	}
+
+	return &PrivateKey{
+		p, psq, u, int64(size),
+		&PublicKey{n, g, h, int64(size)},
+	}, nil
+}
+
+// Function PlaintextBits returns the number of bits of the non-negative
+// plaintexts that can be encrypted and decrypted with the current
+// ou.PublicKey without losing information. The upper half of the plaintext
+// space, which is limited by p, is mapped to negative numbers, so only the
+// lower half is usable.
+func (key *PublicKey) PlaintextBits() int {
+	return int(key.Len) - 2
+}
+
+// Function Encrypt convert the received input big.Int into its encrypted
+// version using the current ou.PublicKey. The absolute value of the input
+// must be lower than 2^(Len-2) to fit into the signed plaintext space. Returns
+// an error if the provided input its too big for the current key ou.PublicKey
+// size or if the random number generation fails. Read more:
+// https://en.wikipedia.org/wiki/Okamoto%E2%80%93Uchiyama_cryptosystem#Encryption
+func (key *PublicKey) Encrypt(input *big.Int) (*big.Int, error) {
+	if input.BitLen() > int(key.Len)-2 {
+		return nil, errors.New("input too long on encrypt")
+	}
+
+	// Calc a large random number (r) that satisfies the condition of
+	// 0 < r < key.N
+	var err error
+	var r *big.Int
+	for {
+		if r, err = rand.Int(rand.Reader, key.N); err != nil {
+			return nil, err
+		} else if r.Sign() != 0 {
+			break
+		}
+	}
+
+	// Compute encrypted message (C) of input (m), where:
+	//		C = g^m * h^r mod n
+	var (
+		gm     = new(big.Int).Exp(key.G, input, key.N)
+		hr     = new(big.Int).Exp(key.H, r, key.N)
+		output = new(big.Int).Mod(new(big.Int).Mul(gm, hr), key.N)
+	)
+
+	return output, nil
+}
+
+// Function Decrypt convert the received encrypted input big.Int into its
+// decrypted version using the current ou.PrivateKey. Returns an error if the
+// provided input its too big for the current key ou.PrivateKey size. Read
+// more:
+// https://en.wikipedia.org/wiki/Okamoto%E2%80%93Uchiyama_cryptosystem#Decryption
+func (key *PrivateKey) Decrypt(input *big.Int) (*big.Int, error) {
+	if input.Sign() < 0 || input.Cmp(key.PubKey.N) != -1 {
+		return nil, errors.New("input too long on decrypt")
+	}
+
+	// Compute decrypted message (D) of input (c), where:
+	//		L(x) = (x - 1) / p
+	//		D = L(c^(p-1) mod p^2) * u mod p
+	var (
+		pl = new(big.Int).Sub(key.p, bOne)
+		cp = new(big.Int).Exp(input, pl, key.psq)
+		l  = new(big.Int).Div(new(big.Int).Sub(cp, bOne), key.p)
+		d  = new(big.Int).Mod(new(big.Int).Mul(l, key.u), key.p)
+	)
+
+	// Parse sign appliying: D'(c) = [D(c)]_p, where:
+	// 		[x]_p = ((x + ⌊p/2⌋) mod p) - ⌊p/2⌋
+	var (
+		p2 = new(big.Int).Div(key.p, bTwo)
+		xp = new(big.Int).Mod(new(big.Int).Add(d, p2), key.p)
+	)
+
+	return new(big.Int).Sub(xp, p2), nil
+}
+
+// Function AddEncrypted returns the result of adding both encrypted big.Int's
+// provided as input (a and b).
+func (key *PublicKey) AddEncrypted(a, b *big.Int) *big.Int {
+	// Compute a + b, where:
+	//		a = E(m1) & b = E(m2)
+	//		a + b = a * b mod n
+	return new(big.Int).Mod(new(big.Int).Mul(a, b), key.N)
+}
+
+// Function Add returns the result of adding the the encrypted big.Int
+// provided as a input to the plain big.Int provided as b input.
+func (key *PublicKey) Add(a, b *big.Int) *big.Int {
+	// Compute a + b, where:
+	//		a = E(m1) & b = m2
+	//		a + b = a * g^b mod n
+	var gb = new(big.Int).Exp(key.G, b, key.N)
+	return new(big.Int).Mod(new(big.Int).Mul(a, gb), key.N)
+}
+
+// Function Mul returns the result of to multiplying the the encrypted big.Int
+// provided as a input to the plain big.Int provided as b input.
+func (key *PublicKey) Mul(a, b *big.Int) *big.Int {
+	// Compute a * b, where:
+	//		a = E(m1) & b = m2
+	//		a * b = a^b mod n
+	return new(big.Int).Exp(a, b, key.N)
+}
@@ -0,0 +1,91 @@
+package ou
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/internal/conformance"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// Ensure that the keys satisfy the sdk interfaces.
+var _ sdk.PublicKey = (*PublicKey)(nil)
+var _ sdk.PrivateKey = (*PrivateKey)(nil)
+
+func TestNewKeys(t *testing.T) {
+	if _, err := NewKeys(64); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if _, err = NewKeys(8); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestNewKeysContext(t *testing.T) {
+	if _, err := NewKeysContext(context.Background(), 64); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewKeysContext(ctx, 4096); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	var key, _ = NewKeys(64)
+	var inputA = new(big.Int).SetInt64(12)
+
+	var err error
+	var encryptedA *big.Int
+	if encryptedA, err = key.PubKey.Encrypt(inputA); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var decryptedA *big.Int
+	if decryptedA, err = key.Decrypt(encryptedA); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	if inputA.Cmp(decryptedA) != 0 {
+		t.Fatalf("expected %d, got %d", inputA, decryptedA)
+	}
+
+	key, err = NewKeys(1024)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	inputA = new(big.Int).SetInt64(-324234987)
+	if encryptedA, err = key.PubKey.Encrypt(inputA); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	if decryptedA, err = key.Decrypt(encryptedA); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	if inputA.Cmp(decryptedA) != 0 {
+		t.Fatalf("expected %d, got %d", inputA, decryptedA)
+	}
+
+	inputA = new(big.Int).Lsh(bOne, uint(key.Len-2))
+	if _, err = key.PubKey.Encrypt(inputA); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	inputA = new(big.Int).Sub(inputA, bOne)
+	if _, err = key.PubKey.Encrypt(inputA); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	if _, err = key.Decrypt(key.PubKey.N); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestConformance(t *testing.T) {
+	var key, _ = NewKeys(256)
+	var bound = new(big.Int).Lsh(bOne, 200)
+	conformance.Run(t, key, key.PubKey, bound)
+}
@@ -0,0 +1,284 @@
+// Package packing allows to encrypt multiple bounded non-negative integers
+// into a single ciphertext, placing each of them into a disjoint bit slot of
+// the same plaintext:
+//
+//	P = v0 + v1 * 2^w + v2 * 2^(2w) + ... + vk * 2^(kw)
+//
+// Each slot has a width w that includes the bits of the original values and a
+// headroom of extra bits reserved to store the carry of the homomorphic
+// operations. Because of the additive homomorphism of the supported
+// cryptosystems, adding two packed ciphertexts results in the slot-wise
+// addition of their values, and multiplying a packed ciphertext by a plain
+// scalar results in the multiplication of every slot by it. The package keeps
+// track of the public upper bound of every slot to detect any overflow before
+// it happens.
+package packing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+var bOne = big.NewInt(1)
+
+// Struct Packer contains the PublicKey used to encrypt and operate the packed
+// values and the packing configuration: the number of slots, the width in bits
+// of the input values and the extra bits reserved into each slot for the
+// results of the homomorphic operations.
+type Packer struct {
+	Key      sdk.PublicKey
+	Slots    int
+	Width    uint
+	Headroom uint
+}
+
+// Struct Packed contains an encrypted packed plaintext and the public upper
+// bound of the values of each of its slots.
+type Packed struct {
+	Value  *big.Int
+	Bounds []*big.Int
+}
+
+// Interface Bounded defines the method that a sdk.PublicKey must provide to
+// let the Packer check that the packed plaintexts fit into its plaintext
+// space: the number of bits of the non-negative plaintexts that can be
+// encrypted and decrypted without losing information. The paillier, ou and
+// elgamal public keys satisfy it.
+type Bounded interface {
+	PlaintextBits() int
+}
+
+// capacity returns the number of bits of the plaintexts that can be encrypted
+// and decrypted by the provided key without losing information, or -1 if the
+// key does not implement the Bounded interface.
+func capacity(key sdk.PublicKey) int {
+	if bounded, ok := key.(Bounded); ok {
+		return bounded.PlaintextBits()
+	}
+	return -1
+}
+
+// checkPacked returns an error if the provided Packed is nil, if its
+// ciphertext is not valid or if it has not a bound for every slot of the
+// Packer.
+func (packer *Packer) checkPacked(packed *Packed) error {
+	if packed == nil || packed.Value == nil {
+		return errors.New("invalid packed ciphertext")
+	} else if len(packed.Bounds) != packer.Slots {
+		return fmt.Errorf("expected %d slot bounds, got %d", packer.Slots, len(packed.Bounds))
+	}
+	for i, bound := range packed.Bounds {
+		if bound == nil || bound.Sign() < 0 {
+			return fmt.Errorf("invalid bound of slot %d", i)
+		}
+	}
+	return nil
+}
+
+// Function NewPacker returns a new Packer with the provided key and
+// configuration. It returns an error if the number of slots or the width are
+// not positive, or if the resulting packed plaintexts do not fit into the
+// plaintext space of the key.
+func NewPacker(key sdk.PublicKey, slots int, width, headroom uint) (*Packer, error) {
+	if slots < 1 {
+		return nil, errors.New("the number of slots must be positive")
+	} else if width < 1 {
+		return nil, errors.New("the width of the slots must be positive")
+	}
+
+	var packer = &Packer{key, slots, width, headroom}
+	if bits := capacity(key); bits >= 0 && packer.size()*uint(slots) > uint(bits) {
+		return nil, fmt.Errorf("%d slots of %d bits do not fit into the %d bits available",
+			slots, packer.size(), bits)
+	}
+
+	return packer, nil
+}
+
+// size returns the total number of bits of every slot.
+func (packer *Packer) size() uint {
+	return packer.Width + packer.Headroom
+}
+
+// limit returns the maximum value that a slot can store, 2^(width+headroom)-1.
+func (packer *Packer) limit() *big.Int {
+	return new(big.Int).Sub(new(big.Int).Lsh(bOne, packer.size()), bOne)
+}
+
+// pack returns the plaintext that contains the provided values packed into
+// consecutive slots. It returns an error if there are more values than slots
+// or if any value is negative or greater than the provided max value.
+func (packer *Packer) pack(values []*big.Int, max *big.Int) (*big.Int, error) {
+	if len(values) > packer.Slots {
+		return nil, fmt.Errorf("too many values, up to %d slots available", packer.Slots)
+	}
+
+	var result = new(big.Int)
+	for i := len(values) - 1; i >= 0; i-- {
+		if values[i].Sign() < 0 || values[i].Cmp(max) > 0 {
+			return nil, fmt.Errorf("value of slot %d out of range [0, %d]", i, max)
+		}
+
+		result.Lsh(result, packer.size())
+		result.Add(result, values[i])
+	}
+
+	return result, nil
+}
+
+// checkBounds returns an error if any of the provided bounds exceeds the
+// maximum value that a slot can store.
+func (packer *Packer) checkBounds(bounds []*big.Int) error {
+	var limit = packer.limit()
+	for i, bound := range bounds {
+		if bound.Cmp(limit) > 0 {
+			return fmt.Errorf("overflow on slot %d: its value could reach %d, but the limit is %d",
+				i, bound, limit)
+		}
+	}
+	return nil
+}
+
+// Function Encrypt packs the provided values into a single plaintext and
+// encrypts it using the Packer key. Every value must be a non-negative integer
+// that fits into the Packer width. The slots without value are filled with
+// zeros. It returns an error if the values can not be packed or if the
+// encryption fails.
+func (packer *Packer) Encrypt(values []*big.Int) (*Packed, error) {
+	var max = new(big.Int).Sub(new(big.Int).Lsh(bOne, packer.Width), bOne)
+	var plaintext, err = packer.pack(values, max)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = &Packed{Bounds: make([]*big.Int, packer.Slots)}
+	if result.Value, err = packer.Key.Encrypt(plaintext); err != nil {
+		return nil, err
+	}
+
+	for i := range result.Bounds {
+		result.Bounds[i] = new(big.Int)
+		if i < len(values) {
+			result.Bounds[i].Set(max)
+		}
+	}
+	return result, nil
+}
+
+// Function AddEncrypted returns the slot-wise addition of both packed
+// ciphertexts provided. It returns an error if any of them does not match
+// the slots of the Packer or if the result of any slot could overflow it.
+func (packer *Packer) AddEncrypted(a, b *Packed) (*Packed, error) {
+	if err := packer.checkPacked(a); err != nil {
+		return nil, err
+	} else if err = packer.checkPacked(b); err != nil {
+		return nil, err
+	}
+
+	var bounds = make([]*big.Int, packer.Slots)
+	for i := range bounds {
+		bounds[i] = new(big.Int).Add(a.Bounds[i], b.Bounds[i])
+	}
+	if err := packer.checkBounds(bounds); err != nil {
+		return nil, err
+	}
+
+	return packer.result(packer.Key.AddEncrypted(a.Value, b.Value), bounds)
+}
+
+// Function Add returns the slot-wise addition of the packed ciphertext and the
+// plain values provided. Every value must be a non-negative integer, and the
+// slots without value are not modified. It returns an error if the packed
+// ciphertext does not match the slots of the Packer, if there are more values
+// than slots or if the result of any slot could overflow it.
+func (packer *Packer) Add(a *Packed, values []*big.Int) (*Packed, error) {
+	if err := packer.checkPacked(a); err != nil {
+		return nil, err
+	}
+
+	var plaintext, err = packer.pack(values, packer.limit())
+	if err != nil {
+		return nil, err
+	}
+
+	var bounds = make([]*big.Int, packer.Slots)
+	for i := range bounds {
+		bounds[i] = new(big.Int).Set(a.Bounds[i])
+		if i < len(values) {
+			bounds[i].Add(bounds[i], values[i])
+		}
+	}
+	if err := packer.checkBounds(bounds); err != nil {
+		return nil, err
+	}
+
+	return packer.result(packer.Key.Add(a.Value, plaintext), bounds)
+}
+
+// Function Mul returns the result of multiplying every slot of the packed
+// ciphertext by the plain scalar provided, which must be non-negative. It
+// returns an error if the packed ciphertext does not match the slots of the
+// Packer or if the result of any slot could overflow it.
+func (packer *Packer) Mul(a *Packed, scalar *big.Int) (*Packed, error) {
+	if err := packer.checkPacked(a); err != nil {
+		return nil, err
+	} else if scalar.Sign() < 0 {
+		return nil, errors.New("the scalar must be non-negative")
+	}
+
+	var bounds = make([]*big.Int, packer.Slots)
+	for i := range bounds {
+		bounds[i] = new(big.Int).Mul(a.Bounds[i], scalar)
+	}
+	if err := packer.checkBounds(bounds); err != nil {
+		return nil, err
+	}
+
+	return packer.result(packer.Key.Mul(a.Value, scalar), bounds)
+}
+
+// result returns a Packed with the provided ciphertext, resulting of an
+// homomorphic operation, and bounds. It returns an error if the ciphertext is
+// nil, which means that the operands were not valid ciphertexts of the key.
+func (packer *Packer) result(value *big.Int, bounds []*big.Int) (*Packed, error) {
+	if value == nil {
+		return nil, errors.New("invalid packed ciphertext")
+	}
+	return &Packed{value, bounds}, nil
+}
+
+// Function Decrypt decrypts the packed ciphertext provided with the private
+// key and returns the value of every slot. It returns an error if the packed
+// ciphertext does not match the slots of the Packer, if the decryption fails
+// or if the value of any slot is greater than its bound, which means that an
+// overflow happened.
+func (packer *Packer) Decrypt(key sdk.PrivateKey, a *Packed) ([]*big.Int, error) {
+	if err := packer.checkPacked(a); err != nil {
+		return nil, err
+	}
+
+	var plaintext, err = key.Decrypt(a.Value)
+	if err != nil {
+		return nil, err
+	} else if plaintext.Sign() < 0 {
+		return nil, errors.New("overflow on the packed plaintext")
+	}
+
+	var mask = packer.limit()
+	var values = make([]*big.Int, packer.Slots)
+	for i := range values {
+		values[i] = new(big.Int).And(plaintext, mask)
+		if values[i].Cmp(a.Bounds[i]) > 0 {
+			return nil, fmt.Errorf("overflow on slot %d", i)
+		}
+		plaintext.Rsh(plaintext, packer.size())
+	}
+
+	if plaintext.Sign() != 0 {
+		return nil, errors.New("overflow on the packed plaintext")
+	}
+	return values, nil
+}
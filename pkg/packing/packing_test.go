@@ -0,0 +1,199 @@
+package packing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/elgamal"
+	"github.com/lucasmenendez/gopaillier/pkg/ou"
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+var key, _ = paillier.NewKeys(256)
+
+var _ Bounded = (*paillier.PublicKey)(nil)
+var _ Bounded = (*ou.PublicKey)(nil)
+var _ Bounded = (*elgamal.PublicKey)(nil)
+
+func toBig(values ...int64) []*big.Int {
+	var result = make([]*big.Int, len(values))
+	for i, value := range values {
+		result[i] = big.NewInt(value)
+	}
+	return result
+}
+
+func checkValues(t *testing.T, expected []int64, result []*big.Int) {
+	for i, value := range expected {
+		if result[i].Cmp(big.NewInt(value)) != 0 {
+			t.Fatalf("slot %d: expected %d, got %d", i, value, result[i])
+		}
+	}
+	for i := len(expected); i < len(result); i++ {
+		if result[i].Sign() != 0 {
+			t.Fatalf("slot %d: expected 0, got %d", i, result[i])
+		}
+	}
+}
+
+func TestNewPacker(t *testing.T) {
+	if _, err := NewPacker(key.PubKey, 8, 32, 8); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if _, err = NewPacker(key.PubKey, 0, 32, 8); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewPacker(key.PubKey, 8, 0, 8); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewPacker(key.PubKey, 16, 32, 8); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	var packer, _ = NewPacker(key.PubKey, 8, 32, 8)
+	var inputs = []int64{0, 1, 4294967295, 12, 3000000000, 7}
+
+	var packed, err = packer.Encrypt(toBig(inputs...))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var result []*big.Int
+	if result, err = packer.Decrypt(key, packed); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	checkValues(t, inputs, result)
+
+	if _, err = packer.Encrypt(toBig(1, 2, 3, 4, 5, 6, 7, 8, 9)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Encrypt(toBig(1, -2)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Encrypt(toBig(4294967296)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestAddEncrypted(t *testing.T) {
+	var packer, _ = NewPacker(key.PubKey, 4, 16, 2)
+	var a, _ = packer.Encrypt(toBig(1, 65535, 300))
+	var b, _ = packer.Encrypt(toBig(2, 65535, 400, 5))
+
+	var sum, err = packer.AddEncrypted(a, b)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	var result, _ = packer.Decrypt(key, sum)
+	checkValues(t, []int64{3, 131070, 700, 5}, result)
+
+	// the headroom of 2 bits allows up to 4 additions of full slots
+	for i := 0; i < 2; i++ {
+		if sum, err = packer.AddEncrypted(sum, b); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	}
+	result, _ = packer.Decrypt(key, sum)
+	checkValues(t, []int64{7, 262140, 1500, 15}, result)
+
+	if _, err = packer.AddEncrypted(sum, b); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestAdd(t *testing.T) {
+	var packer, _ = NewPacker(key.PubKey, 4, 16, 4)
+	var a, _ = packer.Encrypt(toBig(1, 2, 3, 4))
+
+	var sum, err = packer.Add(a, toBig(10, 20, 30))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	var result, _ = packer.Decrypt(key, sum)
+	checkValues(t, []int64{11, 22, 33, 4}, result)
+
+	if _, err = packer.Add(a, toBig(1, -1)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Add(sum, toBig(0, 0, 0, 1048575)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestMul(t *testing.T) {
+	var packer, _ = NewPacker(key.PubKey, 4, 16, 8)
+	var a, _ = packer.Encrypt(toBig(1, 2, 65535))
+
+	var mul, err = packer.Mul(a, big.NewInt(255))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	var result, _ = packer.Decrypt(key, mul)
+	checkValues(t, []int64{255, 510, 16711425}, result)
+
+	if _, err = packer.Mul(a, big.NewInt(257)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Mul(a, big.NewInt(-1)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestOverflowDetection(t *testing.T) {
+	var packer, _ = NewPacker(key.PubKey, 4, 8, 0)
+	var a, _ = packer.Encrypt(toBig(200))
+
+	// force an overflow operating the ciphertext without the packer, so the
+	// carry of the first slot corrupts the second one, which must be empty
+	var forged = &Packed{key.PubKey.Add(a.Value, big.NewInt(100)), a.Bounds}
+	if _, err := packer.Decrypt(key, forged); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// works with other cryptosystems
+	var ouKey, _ = ou.NewKeys(128)
+	if packer, err := NewPacker(ouKey.PubKey, 4, 8, 0); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if a, err = packer.Encrypt(toBig(200, 1)); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if result, err := packer.Decrypt(ouKey, a); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else {
+		checkValues(t, []int64{200, 1}, result)
+	}
+}
+
+func TestMismatchedSlots(t *testing.T) {
+	var packer, _ = NewPacker(key.PubKey, 4, 16, 4)
+	var other, _ = NewPacker(key.PubKey, 2, 16, 4)
+	var a, _ = packer.Encrypt(toBig(1, 2, 3, 4))
+	var b, _ = other.Encrypt(toBig(5, 6))
+
+	if _, err := packer.AddEncrypted(a, b); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.AddEncrypted(b, a); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Add(b, toBig(1)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Add(a, toBig(1, 2, 3, 4, 5)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Mul(b, big.NewInt(2)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Decrypt(key, b); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Decrypt(key, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = packer.Mul(&Packed{Value: a.Value, Bounds: []*big.Int{nil, nil, nil, nil}}, big.NewInt(2)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestInvalidCiphertext(t *testing.T) {
+	var elgamalKey, _ = elgamal.NewKeys(1 << 20)
+	var packer, err = NewPacker(elgamalKey.PubKey, 2, 8, 2)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if _, err = NewPacker(elgamalKey.PubKey, 2, 16, 2); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var invalid = &Packed{Value: big.NewInt(1), Bounds: toBig(0, 0)}
+	if _, err = packer.Mul(invalid, big.NewInt(2)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,44 @@
+package paillier
+
+import (
+	"context"
+	"math/big"
+)
+
+// Function EncryptBatch encrypts every provided input with the current
+// paillier.PublicKey, checking the provided context before every encryption.
+// It returns ctx.Err() if the context is cancelled before all the inputs are
+// encrypted, or an error if any encryption fails.
+func (key *PublicKey) EncryptBatch(ctx context.Context, inputs []*big.Int) ([]*big.Int, error) {
+	var outputs = make([]*big.Int, len(inputs))
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if outputs[i], err = key.Encrypt(input); err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
+
+// Function DecryptBatch decrypts every provided input with the current
+// paillier.PrivateKey, checking the provided context before every decryption.
+// It returns ctx.Err() if the context is cancelled before all the inputs are
+// decrypted, or an error if any decryption fails.
+func (key *PrivateKey) DecryptBatch(ctx context.Context, inputs []*big.Int) ([]*big.Int, error) {
+	var outputs = make([]*big.Int, len(inputs))
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if outputs[i], err = key.Decrypt(input); err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
@@ -0,0 +1,39 @@
+package paillier
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	var key, _ = NewKeys(64)
+	var inputs = []*big.Int{big.NewInt(-3), big.NewInt(0), big.NewInt(42)}
+
+	var encrypted, err = key.PubKey.EncryptBatch(context.Background(), inputs)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var decrypted []*big.Int
+	if decrypted, err = key.DecryptBatch(context.Background(), encrypted); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	for i, input := range inputs {
+		if decrypted[i].Cmp(input) != 0 {
+			t.Fatalf("expected %d, got %d", input, decrypted[i])
+		}
+	}
+
+	if _, err = key.PubKey.EncryptBatch(context.Background(), []*big.Int{key.PubKey.N}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err = key.PubKey.EncryptBatch(ctx, inputs); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	} else if _, err = key.DecryptBatch(ctx, encrypted); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	}
+}
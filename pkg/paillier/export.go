@@ -0,0 +1,195 @@
+package paillier
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Version, key derivation function and cipher of the exported private keys.
+const (
+	ExportVersion = 1
+	exportKDF     = "scrypt"
+	exportCipher  = "aes-256-gcm"
+)
+
+// Maximum scrypt parameters accepted on import, to avoid excessive memory and
+// CPU usage with malicious exported keys.
+const (
+	maxScryptN = 1 << 20
+	maxScryptR = 32
+	maxScryptP = 16
+)
+
+// Struct ScryptParams contains the cost parameters of the scrypt key
+// derivation function: the CPU/memory cost N, the block size R and the
+// parallelization P.
+type ScryptParams struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+// DefaultScryptParams are the scrypt parameters used by PrivateKey.Export,
+// requiring 32 MiB of memory.
+var DefaultScryptParams = ScryptParams{N: 1 << 15, R: 8, P: 1}
+
+// exportKDFParams contains the parameters of the key derivation function of
+// an exported key.
+type exportKDFParams struct {
+	Name string `json:"name"`
+	ScryptParams
+	Salt []byte `json:"salt"`
+}
+
+// exportHeader contains the metadata of an exported key. It is authenticated
+// as additional data of the encryption, so it can not be modified without
+// invalidating the exported key, binding the encrypted private key to its
+// public key fingerprint.
+type exportHeader struct {
+	Version     int             `json:"version"`
+	KDF         exportKDFParams `json:"kdf"`
+	Cipher      string          `json:"cipher"`
+	Fingerprint string          `json:"fingerprint"`
+	PubKey      *PublicKey      `json:"pubKey"`
+}
+
+// exportedKey is the serialized representation of an exported key: its
+// header, the nonce of the encryption and the encrypted private key.
+type exportedKey struct {
+	exportHeader
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// aead returns the AES-256-GCM cipher with the key derived from the provided
+// passphrase and key derivation parameters.
+func (params exportKDFParams) aead(passphrase []byte) (cipher.AEAD, error) {
+	var key, err = scrypt.Key(passphrase, params.Salt, params.N, params.R, params.P, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	var block cipher.Block
+	if block, err = aes.NewCipher(key); err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Function Export returns the current paillier.PrivateKey encrypted with
+// AES-256-GCM under a key derived from the provided passphrase with scrypt,
+// using DefaultScryptParams. The result is a versioned JSON document that
+// includes, in clear but authenticated, the paillier.PublicKey and its
+// fingerprint. It returns an error if the passphrase is empty or if the
+// encryption fails.
+func (key *PrivateKey) Export(passphrase []byte) ([]byte, error) {
+	return key.ExportWithParams(passphrase, DefaultScryptParams)
+}
+
+// Function ExportWithParams works as Export but uses the provided scrypt
+// parameters. It returns an error if the parameters are not valid.
+func (key *PrivateKey) ExportWithParams(passphrase []byte, params ScryptParams) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("the passphrase must not be empty")
+	}
+
+	var exported = exportedKey{exportHeader: exportHeader{
+		Version:     ExportVersion,
+		KDF:         exportKDFParams{Name: exportKDF, ScryptParams: params, Salt: make([]byte, 16)},
+		Cipher:      exportCipher,
+		Fingerprint: key.PubKey.Fingerprint(),
+		PubKey:      key.PubKey,
+	}}
+	if _, err := rand.Read(exported.KDF.Salt); err != nil {
+		return nil, err
+	}
+
+	var header, err = json.Marshal(exported.exportHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	var plain []byte
+	if plain, err = marshalPrivate(key); err != nil {
+		return nil, err
+	}
+
+	var gcm cipher.AEAD
+	if gcm, err = exported.KDF.aead(passphrase); err != nil {
+		return nil, err
+	}
+
+	exported.Nonce = make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(exported.Nonce); err != nil {
+		return nil, err
+	}
+	exported.Ciphertext = gcm.Seal(nil, exported.Nonce, plain, header)
+	return json.Marshal(exported)
+}
+
+// Function ImportPrivateKey returns the paillier.PrivateKey exported with
+// PrivateKey.Export into the provided data, decrypting it with the provided
+// passphrase. It returns an error if the data is malformed, if its version,
+// key derivation function or cipher are not supported, if the passphrase is
+// wrong or if the data, including its metadata, has been modified.
+func ImportPrivateKey(data, passphrase []byte) (*PrivateKey, error) {
+	var exported = new(exportedKey)
+	if err := json.Unmarshal(data, exported); err != nil {
+		return nil, fmt.Errorf("malformed exported key: %w", err)
+	} else if exported.Version != ExportVersion {
+		return nil, fmt.Errorf("unsupported exported key version %d", exported.Version)
+	} else if exported.KDF.Name != exportKDF || exported.Cipher != exportCipher {
+		return nil, fmt.Errorf("unsupported key derivation function '%s' or cipher '%s'", exported.KDF.Name, exported.Cipher)
+	} else if exported.KDF.N > maxScryptN || exported.KDF.R > maxScryptR || exported.KDF.P > maxScryptP {
+		return nil, errors.New("scrypt parameters are too large")
+	} else if exported.PubKey == nil || exported.PubKey.N == nil || exported.PubKey.Fingerprint() != exported.Fingerprint {
+		return nil, errors.New("the public key does not match the fingerprint")
+	}
+
+	var header, err = json.Marshal(exported.exportHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	var gcm cipher.AEAD
+	if gcm, err = exported.KDF.aead(passphrase); err != nil {
+		return nil, err
+	} else if len(exported.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce size")
+	}
+
+	var plain []byte
+	if plain, err = gcm.Open(nil, exported.Nonce, exported.Ciphertext, header); err != nil {
+		return nil, errors.New("wrong passphrase or corrupted exported key")
+	}
+
+	var key *PrivateKey
+	if key, err = unmarshalPrivate(plain); err != nil {
+		return nil, err
+	} else if key.PubKey.Fingerprint() != exported.Fingerprint {
+		return nil, errors.New("the private key does not match the fingerprint")
+	}
+	return key, nil
+}
+
+// Function ExportInsecurePlaintext returns all the parameters of the current
+// paillier.PrivateKey encoded as JSON, including the secret ones in clear,
+// so the result must be kept secret. Use Export to store the key encrypted
+// under a passphrase.
+func (key *PrivateKey) ExportInsecurePlaintext() ([]byte, error) {
+	return marshalPrivate(key)
+}
+
+// Function ImportInsecurePlaintext returns the paillier.PrivateKey encoded
+// in clear into the provided data by PrivateKey.ExportInsecurePlaintext. It
+// returns an error if the data is malformed or if any of the parameters is
+// missing.
+func ImportInsecurePlaintext(data []byte) (*PrivateKey, error) {
+	return unmarshalPrivate(data)
+}
@@ -0,0 +1,92 @@
+package paillier
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+var testParams = ScryptParams{N: 1 << 10, R: 8, P: 1}
+
+func TestExportImport(t *testing.T) {
+	var key, _ = NewKeys(64)
+	var passphrase = []byte("correct horse battery staple")
+
+	var data, err = key.Export(passphrase)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if bytes.Contains(data, []byte(`"d"`)) || bytes.Contains(data, []byte(key.d.String())) {
+		t.Fatal("expected the private parameters to be encrypted")
+	}
+
+	var header map[string]interface{}
+	json.Unmarshal(data, &header)
+	if header["version"] != float64(ExportVersion) || header["fingerprint"] != key.PubKey.Fingerprint() {
+		t.Fatalf("unexpected header %v", header)
+	}
+
+	var imported *PrivateKey
+	if imported, err = ImportPrivateKey(data, passphrase); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var encrypted, _ = key.PubKey.Encrypt(big.NewInt(-42))
+	if decrypted, err := imported.Decrypt(encrypted); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if decrypted.Int64() != -42 {
+		t.Fatalf("expected -42, got %d", decrypted)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	var key, _ = NewKeys(64)
+	var other, _ = NewKeys(64)
+	var passphrase = []byte("passphrase")
+	var data, _ = key.ExportWithParams(passphrase, testParams)
+
+	if _, err := key.Export(nil); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = key.ExportWithParams(passphrase, ScryptParams{N: 1000, R: 8, P: 1}); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = ImportPrivateKey(data, []byte("wrong")); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = ImportPrivateKey([]byte("{"), passphrase); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var tamper = func(field string, value interface{}) []byte {
+		var raw map[string]interface{}
+		json.Unmarshal(data, &raw)
+		raw[field] = value
+		var result, _ = json.Marshal(raw)
+		return result
+	}
+
+	var otherPubKey, _ = json.Marshal(other.PubKey)
+	var otherPubKeyRaw map[string]interface{}
+	json.Unmarshal(otherPubKey, &otherPubKeyRaw)
+	var tests = map[string][]byte{
+		"version":            tamper("version", 2),
+		"cipher":             tamper("cipher", "aes-128-cbc"),
+		"fingerprint":        tamper("fingerprint", other.PubKey.Fingerprint()),
+		"public key":         tamper("pubKey", otherPubKeyRaw),
+		"scrypt parameters":  tamper("n", 1<<30),
+		"scrypt cost change": tamper("n", 1<<11),
+	}
+	for name, tampered := range tests {
+		if _, err := ImportPrivateKey(tampered, passphrase); err == nil {
+			t.Fatalf("%s: expected error, got nil", name)
+		}
+	}
+
+	// replacing both the public key and its fingerprint breaks the
+	// authentication of the metadata
+	var raw map[string]interface{}
+	json.Unmarshal(data, &raw)
+	raw["pubKey"], raw["fingerprint"] = otherPubKeyRaw, other.PubKey.Fingerprint()
+	var swapped, _ = json.Marshal(raw)
+	if _, err := ImportPrivateKey(swapped, passphrase); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
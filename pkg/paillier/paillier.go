@@ -9,9 +9,15 @@
 package paillier
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/internal/prime"
 )
 
 var bOne *big.Int = new(big.Int).SetInt64(1)
@@ -36,6 +42,13 @@ type PrivateKey struct {
 // generation algorithm. Read more:
 // https://en.wikipedia.org/wiki/Paillier_cryptosystem#Key_generation
 func NewKeys(size int) (*PrivateKey, error) {
+	return NewKeysContext(context.Background(), size)
+}
+
+// Function NewKeysContext works as NewKeys but checks the provided context
+// during the generation of the prime numbers, returning ctx.Err() if it is
+// cancelled before the key generation finishes.
+func NewKeysContext(ctx context.Context, size int) (*PrivateKey, error) {
 	var err error
 	if size < 16 {
 		return nil, errors.New("size must be greater than 16")
@@ -43,9 +56,9 @@ func NewKeys(size int) (*PrivateKey, error) {
 
 	// Calc p and q large prime numbers with equivalent length
 	var p, q *big.Int
-	if p, err = rand.Prime(rand.Reader, size); err != nil {
+	if p, err = prime.Generate(ctx, size); err != nil {
 		return nil, err
-	} else if q, err = rand.Prime(rand.Reader, size); err != nil {
+	} else if q, err = prime.Generate(ctx, size); err != nil {
 		return nil, err
 	}
 
@@ -72,43 +85,112 @@ func NewKeys(size int) (*PrivateKey, error) {
 	}, nil
 }
 
+// jsonPrivateKey is the JSON representation of a PrivateKey, including its
+// unexported parameters. PrivateKey does not implement json.Marshaler, so it
+// is only used to export the key, to avoid leaking the secret parameters when
+// a value that contains a PrivateKey is encoded.
+type jsonPrivateKey struct {
+	D      *big.Int   `json:"d"`
+	U      *big.Int   `json:"u"`
+	Len    int64      `json:"len"`
+	PubKey *PublicKey `json:"pubKey"`
+}
+
+// marshalPrivate encodes all the parameters of the provided PrivateKey as
+// JSON, including the secret ones in clear.
+func marshalPrivate(key *PrivateKey) ([]byte, error) {
+	return json.Marshal(jsonPrivateKey{key.d, key.u, key.Len, key.PubKey})
+}
+
+// unmarshalPrivate decodes all the parameters of a PrivateKey encoded with
+// marshalPrivate. It returns an error if any of the parameters is missing.
+func unmarshalPrivate(data []byte) (*PrivateKey, error) {
+	var raw jsonPrivateKey
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	} else if raw.D == nil || raw.U == nil || raw.PubKey == nil ||
+		raw.PubKey.N == nil || raw.PubKey.Nsq == nil || raw.PubKey.G == nil {
+		return nil, errors.New("invalid private key, some parameters are missing")
+	}
+	return &PrivateKey{raw.D, raw.U, raw.Len, raw.PubKey}, nil
+}
+
+// Function Fingerprint returns an identifier of the current
+// paillier.PublicKey, the hexadecimal encoding of the first 16 bytes of the
+// SHA-256 hash of N, that determines the rest of the public parameters.
+func (key *PublicKey) Fingerprint() string {
+	var digest = sha256.Sum256(key.N.Bytes())
+	return hex.EncodeToString(digest[:16])
+}
+
+// Function PlaintextBits returns the number of bits of the non-negative
+// plaintexts that can be encrypted and decrypted with the current
+// paillier.PublicKey without losing information. The upper half of the
+// plaintext space is mapped to negative numbers, so only the lower half is
+// usable.
+func (key *PublicKey) PlaintextBits() int {
+	return key.N.BitLen() - 2
+}
+
 // Function Encrypt convert the received input big.Int into its encrypted
 // version using the current paillier.PublicKey. Returns an error if the
 // provided input its too big for the current key paillier.PublicKey size or
 // if the random number generation fails. Read more:
 // https://en.wikipedia.org/wiki/Paillier_cryptosystem#Encryption
 func (key *PublicKey) Encrypt(input *big.Int) (*big.Int, error) {
+	var output, _, err = key.EncryptWithNonce(input)
+	return output, err
+}
+
+// Function EncryptWithNonce convert the received input big.Int into its
+// encrypted version using the current paillier.PublicKey, as Encrypt does, but
+// it also returns the random number (r) used, which is required to generate
+// zero-knowledge proofs about the encrypted input. The random number must be
+// kept secret. Returns an error if the provided input its too big for the
+// current key paillier.PublicKey size or if the random number generation
+// fails.
+func (key *PublicKey) EncryptWithNonce(input *big.Int) (*big.Int, *big.Int, error) {
 	if input.Cmp(key.N) != -1 {
-		return nil, errors.New("input too long on encrypt")
+		return nil, nil, errors.New("input too long on encrypt")
 	}
 
 	// Calc a large random number (r) that satisfies the condition of
-	// gdc(key.N, r) == 1
+	// 0 < r < key.N and gdc(key.N, r) == 1
+	var r, err = key.randomUnit()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key.encrypt(input, r), r, nil
+}
+
+// encrypt computes the encrypted message (C) of input (m) using the provided
+// random number (r), where:
+//
+//	C = g^m * r^n mod nsq
+func (key *PublicKey) encrypt(input, r *big.Int) *big.Int {
 	var (
-		err  error
-		r    *big.Int
-		size = new(big.Int).SetInt64(key.Len)
+		gm   = new(big.Int).Exp(key.G, input, key.Nsq)
+		rn   = new(big.Int).Exp(r, key.N, key.Nsq)
+		gmrn = new(big.Int).Mul(gm, rn)
 	)
+
+	return gmrn.Mod(gmrn, key.Nsq)
+}
+
+// randomUnit returns a random number (r) that satisfies the condition of
+// 0 < r < key.N and gdc(key.N, r) == 1.
+func (key *PublicKey) randomUnit() (*big.Int, error) {
 	for {
-		if r, err = rand.Int(rand.Reader, size); err != nil {
+		var r, err = rand.Int(rand.Reader, key.N)
+		if err != nil {
 			return nil, err
 		}
 
 		if gdc := new(big.Int).GCD(nil, nil, r, key.N); gdc.Cmp(bOne) == 0 {
-			break
+			return r, nil
 		}
 	}
-
-	// Compute encrypted message (C) of input (m), where:
-	//		C = g^m * r^n mod nsq
-	var (
-		gm     = new(big.Int).Exp(key.G, input, key.Nsq)
-		rn     = new(big.Int).Exp(r, key.N, key.Nsq)
-		gmrn   = new(big.Int).Mul(gm, rn)
-		output = new(big.Int).Mod(gmrn, key.Nsq)
-	)
-
-	return output, nil
 }
 
 // Function Decrypt convert the received encrypted input big.Int into its
@@ -1,8 +1,12 @@
 package paillier
 
 import (
+	"context"
+	"encoding/json"
 	"math/big"
 	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/internal/conformance"
 )
 
 func TestNewKeys(t *testing.T) {
@@ -13,6 +17,18 @@ func TestNewKeys(t *testing.T) {
 	}
 }
 
+func TestNewKeysContext(t *testing.T) {
+	if _, err := NewKeysContext(context.Background(), 64); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewKeysContext(ctx, 4096); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	}
+}
+
 func TestEncryptDecrypt(t *testing.T) {
 	var key, _ = NewKeys(64)
 	var inputA = new(big.Int).SetInt64(12)
@@ -129,3 +145,72 @@ func TestMul(t *testing.T) {
 		t.Fatalf("expected %d, got %d", expectedRes2, result)
 	}
 }
+
+func TestConformance(t *testing.T) {
+	var key, _ = NewKeys(256)
+	var bound = new(big.Int).Lsh(bOne, 400)
+	conformance.Run(t, key, key.PubKey, bound)
+}
+
+func TestPrivateKeyJSON(t *testing.T) {
+	var key, _ = NewKeys(64)
+
+	// encoding a PrivateKey, or a value that contains it, does not leak the
+	// secret parameters
+	var data, err = json.Marshal(struct{ Key *PrivateKey }{key})
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var raw map[string]map[string]interface{}
+	if err = json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	for _, field := range []string{"d", "u", "D", "U"} {
+		if _, ok := raw["Key"][field]; ok {
+			t.Fatalf("unexpected secret parameter %s", field)
+		}
+	}
+}
+
+func TestInsecurePlaintext(t *testing.T) {
+	var key, _ = NewKeys(64)
+	var encrypted, _ = key.PubKey.Encrypt(big.NewInt(-42))
+
+	var data, err = key.ExportInsecurePlaintext()
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var decoded *PrivateKey
+	if decoded, err = ImportInsecurePlaintext(data); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if decoded.Len != key.Len || decoded.PubKey.N.Cmp(key.PubKey.N) != 0 {
+		t.Fatal("expected same key parameters")
+	}
+
+	if decrypted, err := decoded.Decrypt(encrypted); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if decrypted.Int64() != -42 {
+		t.Fatalf("expected -42, got %d", decrypted)
+	}
+
+	if _, err = ImportInsecurePlaintext([]byte(`{"d": 1, "len": 64}`)); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = ImportInsecurePlaintext([]byte(`{`)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	var keyA, _ = NewKeys(64)
+	var keyB, _ = NewKeys(64)
+	var fingerprint = keyA.PubKey.Fingerprint()
+	if len(fingerprint) != 32 {
+		t.Fatalf("expected 32 characters, got %d", len(fingerprint))
+	} else if fingerprint != keyA.PubKey.Fingerprint() {
+		t.Fatal("expected the same fingerprint for the same key")
+	} else if fingerprint == keyB.PubKey.Fingerprint() {
+		t.Fatal("expected different fingerprints for different keys")
+	}
+}
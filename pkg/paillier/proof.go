@@ -0,0 +1,259 @@
+package paillier
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// Struct MembershipProof contains a non-interactive zero-knowledge proof that
+// a ciphertext encrypts one of the values of a public set, without revealing
+// which one. It includes the commitments (A), the challenges (E) and the
+// responses (Z) for every value of the set.
+type MembershipProof struct {
+	A, E, Z []*big.Int
+}
+
+// challengeBits returns the size in bits of the challenges of the proofs. It
+// must be lower than the size of the prime factors of n to keep the proofs
+// sound, so it is limited by the key length.
+func (key *PublicKey) challengeBits() uint {
+	if key.Len-1 < 256 {
+		return uint(key.Len - 1)
+	}
+	return 256
+}
+
+// challenge computes the Fiat–Shamir challenge of the proof hashing with
+// SHA-256 the provided values, and reducing the result to the challenge size.
+func (key *PublicKey) challenge(values ...*big.Int) *big.Int {
+	var hash = sha256.New()
+	for _, value := range values {
+		var raw = value.Bytes()
+		var header = make([]byte, 5)
+		header[0] = byte(value.Sign() + 1)
+		binary.BigEndian.PutUint32(header[1:], uint32(len(raw)))
+		hash.Write(header)
+		hash.Write(raw)
+	}
+
+	var result = new(big.Int).SetBytes(hash.Sum(nil))
+	return result.Mod(result, new(big.Int).Lsh(bOne, key.challengeBits()))
+}
+
+// residues returns the values u_i = c * g^-m_i mod nsq for every m_i of the
+// provided set. The encrypted input c encrypts m_i if and only if u_i is a
+// n-th residue, u_i = r^n mod nsq.
+func (key *PublicKey) residues(encrypted *big.Int, set []*big.Int) []*big.Int {
+	var result = make([]*big.Int, len(set))
+	for i, value := range set {
+		var gm = new(big.Int).Exp(key.G, new(big.Int).Neg(value), key.Nsq)
+		result[i] = gm.Mod(gm.Mul(gm, encrypted), key.Nsq)
+	}
+	return result
+}
+
+// transcript returns the values that are hashed to compute the challenge of a
+// membership proof. If a label is provided, it is appended prefixed with a
+// byte to keep its leading zeros.
+func (key *PublicKey) transcript(encrypted *big.Int, set, commitments []*big.Int, label []byte) []*big.Int {
+	var values = []*big.Int{key.N, key.G, encrypted}
+	values = append(values, set...)
+	values = append(values, commitments...)
+	if label != nil {
+		values = append(values, new(big.Int).SetBytes(append([]byte{1}, label...)))
+	}
+	return values
+}
+
+// Function ProveMembership generates a paillier.MembershipProof that the
+// encrypted big.Int provided encrypts one of the values of the set provided,
+// without revealing which one. It requires the plain input and the random
+// number (nonce) used to encrypt it, as returned by EncryptWithNonce. For
+// example, the set {0, 1} proves that the ciphertext encrypts a bit. It
+// returns an error if the input is not into the set or if the random number
+// generation fails. Read more: https://eprint.iacr.org/2000/008
+func (key *PublicKey) ProveMembership(encrypted, input, nonce *big.Int, set []*big.Int) (*MembershipProof, error) {
+	return key.proveMembership(context.Background(), encrypted, input, nonce, set, nil)
+}
+
+// Function ProveMembershipContext works as ProveMembership but checks the
+// provided context before simulating the proof of every value of the set,
+// whose cost grows with its size, returning ctx.Err() if it is cancelled
+// before the proof is generated.
+func (key *PublicKey) ProveMembershipContext(ctx context.Context, encrypted, input, nonce *big.Int, set []*big.Int) (*MembershipProof, error) {
+	return key.proveMembership(ctx, encrypted, input, nonce, set, nil)
+}
+
+// Function ProveLabeledMembership generates a paillier.MembershipProof like
+// ProveMembership, but binding it to the provided label, such as the
+// identifier of the prover, by including the label in the hashed transcript.
+// The resulting proof is only valid for the same label, which must be checked
+// with VerifyLabeledMembership.
+func (key *PublicKey) ProveLabeledMembership(encrypted, input, nonce *big.Int, set []*big.Int, label []byte) (*MembershipProof, error) {
+	return key.proveMembership(context.Background(), encrypted, input, nonce, set, label)
+}
+
+// proveMembership generates a paillier.MembershipProof bound to the provided
+// label, checking the provided context before processing every value of the
+// set.
+func (key *PublicKey) proveMembership(ctx context.Context, encrypted, input, nonce *big.Int, set []*big.Int, label []byte) (*MembershipProof, error) {
+	var index = -1
+	for i, value := range set {
+		if value.Cmp(input) == 0 {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errors.New("input is not into the set")
+	}
+
+	// Simulate the proofs of the values of the set that are not encrypted,
+	// choosing random challenges (e_i) and responses (z_i), and computing the
+	// commitments (a_i) that satisfy the verification, where:
+	//		a_i = z_i^n * u_i^-e_i mod nsq
+	// For the encrypted value, choose a random ρ and commit to it:
+	//		a_j = ρ^n mod nsq
+	var (
+		proof = &MembershipProof{make([]*big.Int, len(set)), make([]*big.Int, len(set)), make([]*big.Int, len(set))}
+		us    = key.residues(encrypted, set)
+		maxE  = new(big.Int).Lsh(bOne, key.challengeBits())
+		sumE  = new(big.Int)
+		rho   *big.Int
+		err   error
+	)
+	for i := range set {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if i == index {
+			if rho, err = key.randomUnit(); err != nil {
+				return nil, err
+			}
+			proof.A[i] = new(big.Int).Exp(rho, key.N, key.Nsq)
+			continue
+		}
+
+		if proof.E[i], err = rand.Int(rand.Reader, maxE); err != nil {
+			return nil, err
+		} else if proof.Z[i], err = key.randomUnit(); err != nil {
+			return nil, err
+		}
+
+		var ue = new(big.Int).Exp(us[i], proof.E[i], key.Nsq)
+		if ue.ModInverse(ue, key.Nsq) == nil {
+			return nil, errors.New("invalid encrypted input")
+		}
+		var zn = new(big.Int).Exp(proof.Z[i], key.N, key.Nsq)
+		proof.A[i] = zn.Mod(zn.Mul(zn, ue), key.Nsq)
+		sumE.Add(sumE, proof.E[i])
+	}
+
+	// Compute the challenge of the encrypted value (e_j) and its response
+	// (z_j), where:
+	//		e = H(n, g, c, set, a_1, ..., a_k, label)
+	//		e_j = e - Σe_i mod 2^t
+	//		z_j = ρ * r^e_j mod n
+	var e = key.challenge(key.transcript(encrypted, set, proof.A, label)...)
+	proof.E[index] = new(big.Int).Mod(new(big.Int).Sub(e, sumE), maxE)
+	var re = new(big.Int).Exp(nonce, proof.E[index], key.N)
+	proof.Z[index] = re.Mod(re.Mul(re, rho), key.N)
+
+	return proof, nil
+}
+
+// isUnit returns if the provided value is in the range (0, modulus) and it is
+// coprime with n, so it is a valid element of the group of units.
+func (key *PublicKey) isUnit(value, modulus *big.Int) bool {
+	if value == nil || value.Sign() <= 0 || value.Cmp(modulus) >= 0 {
+		return false
+	}
+	return new(big.Int).GCD(nil, nil, value, key.N).Cmp(bOne) == 0
+}
+
+// Function VerifyMembership returns if the paillier.MembershipProof provided
+// is a valid proof that the encrypted big.Int provided encrypts one of the
+// values of the set provided, checking that the ciphertext (c) and the
+// commitments (a_i) are units modulo nsq, that the responses (z_i) are units
+// modulo n, and that:
+//
+//	e = H(n, g, c, set, a_1, ..., a_k) = Σe_i mod 2^t
+//	z_i^n = a_i * u_i^e_i mod nsq, for every i
+func (key *PublicKey) VerifyMembership(encrypted *big.Int, set []*big.Int, proof *MembershipProof) bool {
+	return key.VerifyLabeledMembership(encrypted, set, nil, proof)
+}
+
+// Function VerifyLabeledMembership returns if the paillier.MembershipProof
+// provided is a valid proof that the encrypted big.Int provided encrypts one
+// of the values of the set provided, generated by ProveLabeledMembership with
+// the same label.
+func (key *PublicKey) VerifyLabeledMembership(encrypted *big.Int, set []*big.Int, label []byte, proof *MembershipProof) bool {
+	if proof == nil || len(set) == 0 || len(proof.A) != len(set) || len(proof.E) != len(set) ||
+		len(proof.Z) != len(set) || !key.isUnit(encrypted, key.Nsq) {
+		return false
+	}
+
+	// Check every field before doing any arithmetic with them, the
+	// verification equation holds trivially for zero values.
+	var maxE = new(big.Int).Lsh(bOne, key.challengeBits())
+	for i, value := range set {
+		if value == nil || proof.E[i] == nil || proof.E[i].Sign() < 0 || proof.E[i].Cmp(maxE) >= 0 ||
+			!key.isUnit(proof.A[i], key.Nsq) || !key.isUnit(proof.Z[i], key.N) {
+			return false
+		}
+	}
+
+	var us = key.residues(encrypted, set)
+	var sumE = new(big.Int)
+	for i := range set {
+		var zn = new(big.Int).Exp(proof.Z[i], key.N, key.Nsq)
+		var ue = new(big.Int).Exp(us[i], proof.E[i], key.Nsq)
+		var expected = ue.Mod(ue.Mul(ue, proof.A[i]), key.Nsq)
+		if zn.Cmp(expected) != 0 {
+			return false
+		}
+		sumE.Add(sumE, proof.E[i])
+	}
+
+	var e = key.challenge(key.transcript(encrypted, set, proof.A, label)...)
+	return e.Cmp(sumE.Mod(sumE, maxE)) == 0
+}
+
+// Function ProveDecryption decrypts the encrypted big.Int provided and
+// generates a paillier.MembershipProof that it encrypts the resulting value,
+// that anyone can check with PublicKey.VerifyMembership using the set {m}.
+// To generate it, the random number (r) used to encrypt the input is
+// recovered from the ciphertext (c) with the private key, where:
+//
+//	r = (c * g^-m mod n)^(n^-1 mod λ) mod n
+//
+// The proof is for a set of a single value, so its cost is the same as a
+// decryption and it has no context variant; use DecryptBatch to check a
+// context between ciphertexts. It returns an error if the decryption or the
+// proof generation fails.
+func (key *PrivateKey) ProveDecryption(encrypted *big.Int) (*big.Int, *MembershipProof, error) {
+	var input, err = key.Decrypt(encrypted)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pubKey = key.PubKey
+	var nInv = new(big.Int).ModInverse(pubKey.N, key.d)
+	if nInv == nil {
+		return nil, nil, errors.New("error computing the nonce of the input")
+	}
+
+	var u = pubKey.residues(encrypted, []*big.Int{input})[0]
+	var nonce = new(big.Int).Exp(u.Mod(u, pubKey.N), nInv, pubKey.N)
+
+	var proof *MembershipProof
+	if proof, err = pubKey.ProveMembership(encrypted, input, nonce, []*big.Int{input}); err != nil {
+		return nil, nil, err
+	}
+	return input, proof, nil
+}
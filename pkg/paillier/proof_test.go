@@ -0,0 +1,191 @@
+package paillier
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestMembershipProof(t *testing.T) {
+	var key, _ = NewKeys(128)
+	var bits = []*big.Int{big.NewInt(0), big.NewInt(1)}
+
+	for _, input := range bits {
+		var encrypted, nonce, err = key.PubKey.EncryptWithNonce(input)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		var proof *MembershipProof
+		if proof, err = key.PubKey.ProveMembership(encrypted, input, nonce, bits); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if !key.PubKey.VerifyMembership(encrypted, bits, proof) {
+			t.Fatalf("expected valid proof for %d", input)
+		}
+
+		// the proof is not valid for other ciphertexts or sets
+		var other, _ = key.PubKey.Encrypt(input)
+		if key.PubKey.VerifyMembership(other, bits, proof) {
+			t.Fatal("expected invalid proof for other ciphertext")
+		} else if key.PubKey.VerifyMembership(encrypted, []*big.Int{big.NewInt(1), big.NewInt(0)}, proof) {
+			t.Fatal("expected invalid proof for other set")
+		}
+
+		// tampered proofs are not valid
+		proof.Z[0] = new(big.Int).Add(proof.Z[0], bOne)
+		if key.PubKey.VerifyMembership(encrypted, bits, proof) {
+			t.Fatal("expected invalid tampered proof")
+		}
+	}
+
+	var set = []*big.Int{big.NewInt(-1), big.NewInt(5), big.NewInt(42)}
+	var input = big.NewInt(-1)
+	var encrypted, nonce, _ = key.PubKey.EncryptWithNonce(input)
+	if proof, err := key.PubKey.ProveMembership(encrypted, input, nonce, set); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if !key.PubKey.VerifyMembership(encrypted, set, proof) {
+		t.Fatal("expected valid proof")
+	} else if key.PubKey.VerifyMembership(encrypted, set[:2], proof) {
+		t.Fatal("expected invalid proof for other set")
+	}
+
+	// a value out of the set can not be proved, and forging a proof for it
+	// with a wrong input does not work
+	input = big.NewInt(2)
+	encrypted, nonce, _ = key.PubKey.EncryptWithNonce(input)
+	if _, err := key.PubKey.ProveMembership(encrypted, input, nonce, bits); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if proof, err := key.PubKey.ProveMembership(encrypted, bOne, nonce, bits); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if key.PubKey.VerifyMembership(encrypted, bits, proof) {
+		t.Fatal("expected invalid forged proof")
+	}
+}
+
+func TestForgedMembershipProof(t *testing.T) {
+	var key, _ = NewKeys(128)
+	var bits = []*big.Int{big.NewInt(0), big.NewInt(1)}
+	var maxE = new(big.Int).Lsh(bOne, key.PubKey.challengeBits())
+
+	// forge a proof with zero responses, whose verification equation holds
+	// for a zero ciphertext with any commitments and challenges
+	var forge = func(encrypted *big.Int) *MembershipProof {
+		var proof = &MembershipProof{
+			A: []*big.Int{big.NewInt(1), big.NewInt(1)},
+			Z: []*big.Int{big.NewInt(0), big.NewInt(0)},
+		}
+		var e = key.PubKey.challenge(key.PubKey.transcript(encrypted, bits, proof.A, nil)...)
+		proof.E = []*big.Int{big.NewInt(1), new(big.Int).Mod(new(big.Int).Sub(e, bOne), maxE)}
+		return proof
+	}
+	for _, encrypted := range []*big.Int{big.NewInt(0), new(big.Int).Set(key.PubKey.N), new(big.Int).Set(key.PubKey.Nsq)} {
+		if key.PubKey.VerifyMembership(encrypted, bits, forge(encrypted)) {
+			t.Fatalf("expected invalid forged proof for %d", encrypted)
+		}
+	}
+
+	// a valid proof with a zero response or commitment, or with nil or
+	// missing fields, is not valid
+	var encrypted, nonce, _ = key.PubKey.EncryptWithNonce(bOne)
+	var proof, _ = key.PubKey.ProveMembership(encrypted, bOne, nonce, bits)
+	var tamper = []func(*MembershipProof){
+		func(p *MembershipProof) { p.Z[0] = big.NewInt(0) },
+		func(p *MembershipProof) { p.Z[1] = new(big.Int).Set(key.PubKey.N) },
+		func(p *MembershipProof) { p.A[0] = big.NewInt(0) },
+		func(p *MembershipProof) { p.A[1] = nil },
+		func(p *MembershipProof) { p.E[0] = nil },
+		func(p *MembershipProof) { p.Z = p.Z[:1] },
+	}
+	for i, fn := range tamper {
+		var copied = &MembershipProof{
+			append([]*big.Int{}, proof.A...),
+			append([]*big.Int{}, proof.E...),
+			append([]*big.Int{}, proof.Z...),
+		}
+		fn(copied)
+		if key.PubKey.VerifyMembership(encrypted, bits, copied) {
+			t.Fatalf("%d: expected invalid tampered proof", i)
+		}
+	}
+	if !key.PubKey.VerifyMembership(encrypted, bits, proof) {
+		t.Fatal("expected valid proof")
+	} else if key.PubKey.VerifyMembership(nil, bits, proof) {
+		t.Fatal("expected invalid proof for nil ciphertext")
+	} else if key.PubKey.VerifyMembership(encrypted, []*big.Int{nil, bOne}, proof) {
+		t.Fatal("expected invalid proof for nil set value")
+	}
+}
+
+func TestProveMembershipContext(t *testing.T) {
+	var key, _ = NewKeys(128)
+	var set = []*big.Int{big.NewInt(3), big.NewInt(5), big.NewInt(7)}
+	var encrypted, nonce, _ = key.PubKey.EncryptWithNonce(set[1])
+
+	var proof, err = key.PubKey.ProveMembershipContext(context.Background(), encrypted, set[1], nonce, set)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if !key.PubKey.VerifyMembership(encrypted, set, proof) {
+		t.Fatal("expected valid proof")
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err = key.PubKey.ProveMembershipContext(ctx, encrypted, set[1], nonce, set); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	}
+}
+
+func TestLabeledMembershipProof(t *testing.T) {
+	var key, _ = NewKeys(128)
+	var bits = []*big.Int{big.NewInt(0), big.NewInt(1)}
+	var encrypted, nonce, _ = key.PubKey.EncryptWithNonce(bOne)
+
+	var proof, err = key.PubKey.ProveLabeledMembership(encrypted, bOne, nonce, bits, []byte("alice"))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if !key.PubKey.VerifyLabeledMembership(encrypted, bits, []byte("alice"), proof) {
+		t.Fatal("expected valid proof")
+	}
+
+	// the proof is only valid for the same label
+	for _, label := range [][]byte{nil, {}, []byte("bob"), append([]byte{0}, "alice"...)} {
+		if key.PubKey.VerifyLabeledMembership(encrypted, bits, label, proof) {
+			t.Fatalf("expected invalid proof for label %q", label)
+		}
+	}
+	if key.PubKey.VerifyMembership(encrypted, bits, proof) {
+		t.Fatal("expected invalid proof without label")
+	}
+}
+
+func TestProveDecryption(t *testing.T) {
+	var key, _ = NewKeys(128)
+	for _, input := range []int64{0, 1, -25, 123456789} {
+		var encrypted, _ = key.PubKey.Encrypt(big.NewInt(input))
+		var decrypted, proof, err = key.ProveDecryption(encrypted)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if decrypted.Int64() != input {
+			t.Fatalf("expected %d, got %d", input, decrypted)
+		} else if !key.PubKey.VerifyMembership(encrypted, []*big.Int{decrypted}, proof) {
+			t.Fatal("expected valid proof")
+		}
+
+		var wrong = new(big.Int).Add(decrypted, bOne)
+		if key.PubKey.VerifyMembership(encrypted, []*big.Int{wrong}, proof) {
+			t.Fatal("expected invalid proof")
+		}
+	}
+
+	// the proof is also valid for results of homomorphic operations
+	var a, _ = key.PubKey.Encrypt(big.NewInt(20))
+	var b, _ = key.PubKey.Encrypt(big.NewInt(-5))
+	var sum = key.PubKey.AddEncrypted(key.PubKey.Mul(a, big.NewInt(3)), b)
+	if decrypted, proof, err := key.ProveDecryption(sum); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if decrypted.Int64() != 55 {
+		t.Fatalf("expected 55, got %d", decrypted)
+	} else if !key.PubKey.VerifyMembership(sum, []*big.Int{decrypted}, proof) {
+		t.Fatal("expected valid proof")
+	}
+}
@@ -0,0 +1,218 @@
+// Package pir implements single-server private information retrieval (PIR)
+// over Paillier, allowing a client to fetch a row of a database held by a
+// server without revealing which row. The client sends a selection vector of
+// encryptions, E(1) in the position of the desired row and E(0) in the rest,
+// and the server computes the homomorphic inner product between the vector
+// and its plain rows:
+//
+//	Π E(s_i)^{r_i} = E(Σ s_i * r_i) = E(r_index)
+//
+// which only the client can decrypt. The query of the basic variant has as many
+// encryptions as rows. The recursive variant arranges the rows as a matrix of
+// ⌈√n⌉ columns and sends two selection vectors, one for the column and another
+// for the row, reducing the query to O(√n) encryptions: the server selects the
+// column of every matrix row, splits every resulting ciphertext into two
+// digits in base N and selects the desired digits using the row vector. The
+// client decrypts the digits, rebuilds the inner ciphertext and decrypts it.
+package pir
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+var zero = big.NewInt(0)
+var one = big.NewInt(1)
+
+// Struct Query contains the encrypted selection vector of a basic PIR query.
+type Query struct {
+	Selection []*big.Int
+}
+
+// Struct RecursiveQuery contains the encrypted selection vectors of a recursive
+// PIR query, one to select the column and another to select the row of the
+// database arranged as a matrix.
+type RecursiveQuery struct {
+	Columns []*big.Int
+	Rows    []*big.Int
+}
+
+// Struct Server contains the paillier.PublicKey of the client and the plain
+// rows of the database, every of them between 0 and N - 1.
+type Server struct {
+	PubKey *paillier.PublicKey
+	Rows   []*big.Int
+}
+
+// Function Dimensions returns the number of rows and columns of the matrix
+// used by the recursive variant to arrange a database of the provided size,
+// with ⌈√size⌉ columns.
+func Dimensions(size int) (int, int) {
+	var columns = 1
+	for columns*columns < size {
+		columns++
+	}
+	return (size + columns - 1) / columns, columns
+}
+
+// selection returns an encrypted selection vector of the provided size with
+// E(1) in the provided index and E(0) in the rest.
+func selection(pubKey *paillier.PublicKey, size, index int) ([]*big.Int, error) {
+	var vector = make([]*big.Int, size)
+	for i := range vector {
+		var bit = zero
+		if i == index {
+			bit = one
+		}
+
+		var err error
+		if vector[i], err = pubKey.Encrypt(bit); err != nil {
+			return nil, err
+		}
+	}
+	return vector, nil
+}
+
+// innerProduct returns the homomorphic inner product between the provided
+// encrypted selection vector and plain values, using the provided
+// paillier.PublicKey. The values without pair into the vector are ignored.
+func innerProduct(pubKey *paillier.PublicKey, vector, values []*big.Int) *big.Int {
+	var result = new(big.Int).Set(one)
+	for i, value := range values {
+		if value.Sign() == 0 {
+			continue
+		}
+		result = pubKey.AddEncrypted(result, pubKey.Mul(vector[i], value))
+	}
+	return result
+}
+
+// Function NewQuery returns a basic Query to retrieve the row with the
+// provided index from a database with the provided number of rows. It returns
+// an error if the index is out of range or if the encryption fails.
+func NewQuery(pubKey *paillier.PublicKey, size, index int) (*Query, error) {
+	if index < 0 || index >= size {
+		return nil, fmt.Errorf("index %d out of range [0, %d)", index, size)
+	}
+
+	var vector, err = selection(pubKey, size, index)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{Selection: vector}, nil
+}
+
+// Function NewRecursiveQuery returns a RecursiveQuery to retrieve the row with
+// the provided index from a database with the provided number of rows. It
+// returns an error if the index is out of range or if the encryption fails.
+func NewRecursiveQuery(pubKey *paillier.PublicKey, size, index int) (*RecursiveQuery, error) {
+	if index < 0 || index >= size {
+		return nil, fmt.Errorf("index %d out of range [0, %d)", index, size)
+	}
+
+	var rows, columns = Dimensions(size)
+	var query = new(RecursiveQuery)
+	var err error
+	if query.Columns, err = selection(pubKey, columns, index%columns); err != nil {
+		return nil, err
+	} else if query.Rows, err = selection(pubKey, rows, index/columns); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// Function NewServer returns a new Server with the provided paillier.PublicKey
+// and database rows. It returns an error if any row is negative or not lower
+// than N.
+func NewServer(pubKey *paillier.PublicKey, rows []*big.Int) (*Server, error) {
+	for i, row := range rows {
+		if row.Sign() < 0 || row.Cmp(pubKey.N) >= 0 {
+			return nil, fmt.Errorf("row %d out of range [0, N)", i)
+		}
+	}
+	return &Server{PubKey: pubKey, Rows: rows}, nil
+}
+
+// Function Answer returns the encrypted row selected by the provided Query,
+// computing the homomorphic inner product between its selection vector and
+// the rows of the database. It returns an error if the size of the selection
+// vector does not match the number of rows.
+func (server *Server) Answer(query *Query) (*big.Int, error) {
+	if len(query.Selection) != len(server.Rows) {
+		return nil, errors.New("the query size does not match the database size")
+	}
+	return innerProduct(server.PubKey, query.Selection, server.Rows), nil
+}
+
+// Function AnswerRecursive returns the encrypted digits, in base N, of the
+// encrypted row selected by the provided RecursiveQuery. It returns an error
+// if the size of the selection vectors does not match the dimensions of the
+// database.
+func (server *Server) AnswerRecursive(query *RecursiveQuery) ([]*big.Int, error) {
+	var rows, columns = Dimensions(len(server.Rows))
+	if len(query.Rows) != rows || len(query.Columns) != columns {
+		return nil, errors.New("the query size does not match the database dimensions")
+	}
+
+	// select the column of every matrix row and split the resulting
+	// ciphertexts, lower than N^2, into two digits lower than N
+	var low, high = make([]*big.Int, rows), make([]*big.Int, rows)
+	for r := 0; r < rows; r++ {
+		var end = (r + 1) * columns
+		if end > len(server.Rows) {
+			end = len(server.Rows)
+		}
+
+		var selected = innerProduct(server.PubKey, query.Columns, server.Rows[r*columns:end])
+		high[r], low[r] = new(big.Int).DivMod(selected, server.PubKey.N, new(big.Int))
+	}
+
+	return []*big.Int{
+		innerProduct(server.PubKey, query.Rows, low),
+		innerProduct(server.PubKey, query.Rows, high),
+	}, nil
+}
+
+// decrypt returns the decryption of the provided input with the provided
+// paillier.PrivateKey between 0 and N - 1, undoing the signed decoding.
+func decrypt(key *paillier.PrivateKey, input *big.Int) (*big.Int, error) {
+	var result, err = key.Decrypt(input)
+	if err != nil {
+		return nil, err
+	} else if result.Sign() < 0 {
+		result.Add(result, key.PubKey.N)
+	}
+	return result, nil
+}
+
+// Function Decode returns the plain row contained into the provided answer of
+// a basic Query, decrypting it with the provided paillier.PrivateKey.
+func Decode(key *paillier.PrivateKey, answer *big.Int) (*big.Int, error) {
+	return decrypt(key, answer)
+}
+
+// Function DecodeRecursive returns the plain row contained into the provided
+// answer of a RecursiveQuery, decrypting its digits with the provided
+// paillier.PrivateKey, rebuilding the inner ciphertext and decrypting it. It
+// returns an error if the answer is malformed or if any decryption fails.
+func DecodeRecursive(key *paillier.PrivateKey, answer []*big.Int) (*big.Int, error) {
+	if len(answer) != 2 {
+		return nil, errors.New("malformed recursive answer")
+	}
+
+	var low, err = decrypt(key, answer[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var high *big.Int
+	if high, err = decrypt(key, answer[1]); err != nil {
+		return nil, err
+	}
+
+	var inner = new(big.Int).Add(new(big.Int).Mul(high, key.PubKey.N), low)
+	return decrypt(key, inner)
+}
@@ -0,0 +1,105 @@
+package pir
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+var key, _ = paillier.NewKeys(128)
+
+func database(size int) []*big.Int {
+	var rows = make([]*big.Int, size)
+	for i := range rows {
+		rows[i] = big.NewInt(int64(i*i + 7))
+	}
+	rows[size/2] = big.NewInt(0)
+	rows[size-1] = new(big.Int).Sub(key.PubKey.N, big.NewInt(1))
+	return rows
+}
+
+func TestDimensions(t *testing.T) {
+	var tests = []struct{ size, rows, columns int }{
+		{1, 1, 1}, {4, 2, 2}, {5, 2, 3}, {10, 3, 4}, {16, 4, 4}, {17, 4, 5},
+	}
+	for _, test := range tests {
+		if rows, columns := Dimensions(test.size); rows != test.rows || columns != test.columns {
+			t.Fatalf("%d: expected %dx%d, got %dx%d", test.size, test.rows, test.columns, rows, columns)
+		}
+	}
+}
+
+func TestPIR(t *testing.T) {
+	var rows = database(10)
+	var server, err = NewServer(key.PubKey, rows)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	for index, expected := range rows {
+		var query *Query
+		if query, err = NewQuery(key.PubKey, len(rows), index); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		var answer, row *big.Int
+		if answer, err = server.Answer(query); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if row, err = Decode(key, answer); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if row.Cmp(expected) != 0 {
+			t.Fatalf("%d: expected %d, got %d", index, expected, row)
+		}
+	}
+}
+
+func TestRecursivePIR(t *testing.T) {
+	for _, size := range []int{1, 7, 10, 16} {
+		var rows = database(size)
+		var server, err = NewServer(key.PubKey, rows)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		for index, expected := range rows {
+			var query *RecursiveQuery
+			if query, err = NewRecursiveQuery(key.PubKey, size, index); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+
+			var answer []*big.Int
+			var row *big.Int
+			if answer, err = server.AnswerRecursive(query); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			} else if row, err = DecodeRecursive(key, answer); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			} else if row.Cmp(expected) != 0 {
+				t.Fatalf("%d/%d: expected %d, got %d", index, size, expected, row)
+			}
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewServer(key.PubKey, []*big.Int{big.NewInt(-1)}); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewServer(key.PubKey, []*big.Int{key.PubKey.N}); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewQuery(key.PubKey, 4, 4); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewRecursiveQuery(key.PubKey, 4, -1); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var server, _ = NewServer(key.PubKey, database(10))
+	var query, _ = NewQuery(key.PubKey, 9, 0)
+	var recursive, _ = NewRecursiveQuery(key.PubKey, 20, 0)
+	if _, err := server.Answer(query); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = server.AnswerRecursive(recursive); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = DecodeRecursive(key, []*big.Int{big.NewInt(1)}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,115 @@
+// Package polynomial allows to evaluate polynomials with encrypted
+// number.Number coefficients at plain points without decrypting them:
+//
+//	E(P(x)) = Σ E(a_i) * x^i
+//
+// The evaluation uses the Horner method, that only requires the homomorphic
+// multiplication by a plain number and the homomorphic addition between
+// encrypted numbers:
+//
+//	E(P(x)) = (...(E(a_k) * x + E(a_k-1)) * x + ...) * x + E(a_0)
+//
+// The coefficients and the points could be floating point numbers, every
+// multiplication adds the exponent of the point to the exponent of the partial
+// result and every addition aligns the exponents of both operands. The points
+// are normalized before the evaluation to use the greatest possible exponent
+// and reduce the growth of the encrypted value, that must remain lower than
+// N/2 in absolute value to be decrypted correctly.
+package polynomial
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// Struct Polynomial contains the sdk.PublicKey used to operate the polynomial
+// and its encrypted coefficients, from the constant term a_0 to the leading
+// one a_k.
+type Polynomial struct {
+	PubKey       sdk.PublicKey
+	Coefficients []*number.Number
+}
+
+// Function New returns a new Polynomial with the provided sdk.PublicKey and
+// encrypted coefficients, from the constant term to the leading one. It
+// returns an error if no coefficients are provided or if any of them is not
+// encrypted.
+func New(pubKey sdk.PublicKey, coefficients []*number.Number) (*Polynomial, error) {
+	if len(coefficients) == 0 {
+		return nil, errors.New("no coefficients provided")
+	}
+
+	for i, coefficient := range coefficients {
+		if !coefficient.IsEncrypted() {
+			return nil, fmt.Errorf("coefficient %d must be encrypted", i)
+		}
+	}
+	return &Polynomial{PubKey: pubKey, Coefficients: coefficients}, nil
+}
+
+// Function Encrypt returns a new Polynomial with the provided plain
+// coefficients, from the constant term to the leading one, encrypted with the
+// provided sdk.PublicKey. It returns an error if no coefficients are provided,
+// if any of them is encrypted or if the encryption fails.
+func Encrypt(pubKey sdk.PublicKey, coefficients []*number.Number) (*Polynomial, error) {
+	var client = sdk.NewClient(nil, pubKey)
+	var encrypted = make([]*number.Number, len(coefficients))
+	for i, coefficient := range coefficients {
+		if coefficient.IsEncrypted() {
+			return nil, fmt.Errorf("coefficient %d must not be encrypted", i)
+		}
+
+		var err error
+		if encrypted[i], err = client.Encrypt(coefficient); err != nil {
+			return nil, err
+		}
+	}
+	return New(pubKey, encrypted)
+}
+
+// Function Degree returns the degree of the current Polynomial, the index of
+// its leading coefficient.
+func (poly *Polynomial) Degree() int {
+	return len(poly.Coefficients) - 1
+}
+
+// Function Evaluate returns the encrypted evaluation of the current Polynomial
+// at the provided plain point, using the Horner method. It returns an error if
+// the point is encrypted.
+func (poly *Polynomial) Evaluate(point *number.Number) (*number.Number, error) {
+	if point.IsEncrypted() {
+		return nil, errors.New("the point provided must not be encrypted")
+	}
+
+	var x, err = new(number.Number).Set(point).Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	var result = poly.Coefficients[len(poly.Coefficients)-1]
+	for i := len(poly.Coefficients) - 2; i >= 0; i-- {
+		if result, err = sdk.Mul(poly.PubKey, result, x); err != nil {
+			return nil, err
+		} else if result, err = sdk.AddEncrypted(poly.PubKey, result, poly.Coefficients[i]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Function EvaluateBatch returns the encrypted evaluations of the current
+// Polynomial at every provided plain point, in the same order. It returns an
+// error if any point is encrypted.
+func (poly *Polynomial) EvaluateBatch(points []*number.Number) ([]*number.Number, error) {
+	var results = make([]*number.Number, len(points))
+	for i, point := range points {
+		var err error
+		if results[i], err = poly.Evaluate(point); err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,85 @@
+package polynomial
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+var client, _ = sdk.InitClient(256)
+
+func numbers(t *testing.T, inputs ...float64) []*number.Number {
+	var result = make([]*number.Number, len(inputs))
+	for i, input := range inputs {
+		var err error
+		if result[i], err = new(number.Number).SetFloat(input); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	}
+	return result
+}
+
+func TestEvaluate(t *testing.T) {
+	// P(x) = 3 - 2x + 0.5x^2 + 1.25x^3
+	var coefficients = []float64{3, -2, 0.5, 1.25}
+	var poly, err = Encrypt(client.PubKey, numbers(t, coefficients...))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if poly.Degree() != 3 {
+		t.Fatalf("expected 3, got %d", poly.Degree())
+	}
+
+	var points = []float64{0, 1, -1, 2, 0.1, -3.5, 100}
+	var results []*number.Number
+	if results, err = poly.EvaluateBatch(numbers(t, points...)); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	for i, x := range points {
+		var expected = 0.0
+		for j, coefficient := range coefficients {
+			expected += coefficient * math.Pow(x, float64(j))
+		}
+
+		var decrypted, err = client.Decrypt(results[i])
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if fmt.Sprintf("%.6f", decrypted.Float()) != fmt.Sprintf("%.6f", expected) {
+			t.Fatalf("P(%v): expected %.6f, got %.6f", x, expected, decrypted.Float())
+		}
+	}
+}
+
+func TestConstant(t *testing.T) {
+	var poly, _ = Encrypt(client.PubKey, numbers(t, 4.5))
+	var result, err = poly.Evaluate(new(number.Number).SetInt(10))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var decrypted, _ = client.Decrypt(result)
+	if decrypted.Float() != 4.5 {
+		t.Fatalf("expected 4.5, got %f", decrypted.Float())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var plain = numbers(t, 1, 2)
+	if _, err := New(client.PubKey, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = New(client.PubKey, plain); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var poly, _ = Encrypt(client.PubKey, plain)
+	if _, err := Encrypt(client.PubKey, poly.Coefficients); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = poly.Evaluate(poly.Coefficients[0]); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = poly.EvaluateBatch([]*number.Number{plain[0], poly.Coefficients[0]}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
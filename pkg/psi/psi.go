@@ -0,0 +1,266 @@
+// Package psi implements private set intersection (PSI) over Paillier based on
+// encrypted polynomial evaluation. The client encodes its set X as the roots
+// of a polynomial P(z) = Π (z - x_i) mod N and sends its encrypted
+// coefficients to the server. For every element y of its set, the server
+// evaluates the polynomial homomorphically, randomizes the result with a
+// random factor r and a fresh encryption of zero and, depending on the mode,
+// returns:
+//
+//	intersection: E(r * P(y) + y)
+//	cardinality:  E(r * P(y))
+//
+// shuffled. If y belongs to X, P(y) = 0, so the client obtains y (or 0) after
+// the decryption, otherwise it obtains a random value. In cardinality mode the
+// client only learns how many elements are shared, but not which ones. The
+// server does not learn anything about the client set apart from its size.
+// The elements are hashed with SHA-256 into Z_N before operating them.
+package psi
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+// Supported modes of the PSI protocol.
+const (
+	ModeIntersection = "intersection"
+	ModeCardinality  = "cardinality"
+)
+
+// Interface Transport defines how the client sends the encrypted coefficients
+// of its polynomial and the requested mode to the server, and receives the
+// encrypted evaluations, allowing to use the PSI protocol over any channel.
+type Transport interface {
+	Send(mode string, coefficients []*big.Int) ([]*big.Int, error)
+}
+
+// Struct MemoryTransport implements the Transport interface calling directly
+// the Server provided, for testing or for cases where both actors run in the
+// same process.
+type MemoryTransport struct {
+	Server *Server
+}
+
+// Function Send sends the mode and the encrypted coefficients to the Server and
+// returns its encrypted evaluations.
+func (transport *MemoryTransport) Send(mode string, coefficients []*big.Int) ([]*big.Int, error) {
+	return transport.Server.Handle(mode, coefficients)
+}
+
+// Struct Client contains the paillier.PrivateKey to encrypt the polynomial and
+// decrypt the evaluations, and the set of the client indexed by the hash of
+// its elements.
+type Client struct {
+	Key      *paillier.PrivateKey
+	elements map[string]string
+}
+
+// Struct Server contains the paillier.PublicKey of the client and the hashes of
+// the elements of the server set.
+type Server struct {
+	PubKey *paillier.PublicKey
+	hashes []*big.Int
+}
+
+// hash returns the SHA-256 hash of the provided element reduced modulo N.
+func hash(element string, n *big.Int) *big.Int {
+	var digest = sha256.Sum256([]byte(element))
+	return new(big.Int).Mod(new(big.Int).SetBytes(digest[:]), n)
+}
+
+// Function NewClient returns a new Client with the provided
+// paillier.PrivateKey and set. Duplicated elements are ignored. It returns an
+// error if the set is empty.
+func NewClient(key *paillier.PrivateKey, set []string) (*Client, error) {
+	if len(set) == 0 {
+		return nil, errors.New("the client set must not be empty")
+	}
+
+	var client = &Client{Key: key, elements: map[string]string{}}
+	for _, element := range set {
+		client.elements[hash(element, key.PubKey.N).String()] = element
+	}
+	return client, nil
+}
+
+// Function NewServer returns a new Server with the provided client
+// paillier.PublicKey and server set. Duplicated elements are ignored.
+func NewServer(pubKey *paillier.PublicKey, set []string) *Server {
+	var server = &Server{PubKey: pubKey}
+	var seen = map[string]bool{}
+	for _, element := range set {
+		var h = hash(element, pubKey.N)
+		if !seen[h.String()] {
+			seen[h.String()] = true
+			server.hashes = append(server.hashes, h)
+		}
+	}
+	return server
+}
+
+// Function Coefficients returns the encrypted coefficients, from the constant
+// term to the leading one, of the polynomial P(z) = Π (z - x_i) mod N, whose
+// roots are the hashes of the elements of the client set.
+func (client *Client) Coefficients() ([]*big.Int, error) {
+	var n = client.Key.PubKey.N
+	var coefficients = []*big.Int{big.NewInt(1)}
+	for h := range client.elements {
+		var root, _ = new(big.Int).SetString(h, 10)
+		// multiply the current polynomial by (z - root)
+		var next = make([]*big.Int, len(coefficients)+1)
+		next[len(coefficients)] = big.NewInt(0)
+		for i := range coefficients {
+			next[i] = big.NewInt(0)
+		}
+		for i, coefficient := range coefficients {
+			next[i+1].Add(next[i+1], coefficient)
+			next[i].Sub(next[i], new(big.Int).Mul(coefficient, root))
+		}
+		for i := range next {
+			next[i].Mod(next[i], n)
+		}
+		coefficients = next
+	}
+
+	var encrypted = make([]*big.Int, len(coefficients))
+	for i, coefficient := range coefficients {
+		var err error
+		if encrypted[i], err = client.Key.PubKey.Encrypt(coefficient); err != nil {
+			return nil, err
+		}
+	}
+	return encrypted, nil
+}
+
+// decrypt returns the decryption of the provided input between 0 and N - 1,
+// undoing the signed decoding.
+func (client *Client) decrypt(input *big.Int) (*big.Int, error) {
+	var result, err = client.Key.Decrypt(input)
+	if err != nil {
+		return nil, err
+	} else if result.Sign() < 0 {
+		result.Add(result, client.Key.PubKey.N)
+	}
+	return result, nil
+}
+
+// Function Intersection returns the elements of the client set that also
+// belong to the server set, running the PSI protocol in intersection mode with
+// the server through the provided Transport.
+func (client *Client) Intersection(transport Transport) ([]string, error) {
+	var coefficients, err = client.Coefficients()
+	if err != nil {
+		return nil, err
+	}
+
+	var evaluations []*big.Int
+	if evaluations, err = transport.Send(ModeIntersection, coefficients); err != nil {
+		return nil, err
+	}
+
+	var intersection []string
+	for _, evaluation := range evaluations {
+		var value *big.Int
+		if value, err = client.decrypt(evaluation); err != nil {
+			return nil, err
+		} else if element, ok := client.elements[value.String()]; ok {
+			intersection = append(intersection, element)
+		}
+	}
+	return intersection, nil
+}
+
+// Function Cardinality returns the number of elements shared by the client and
+// server sets, running the PSI protocol in cardinality mode with the server
+// through the provided Transport.
+func (client *Client) Cardinality(transport Transport) (int, error) {
+	var coefficients, err = client.Coefficients()
+	if err != nil {
+		return 0, err
+	}
+
+	var evaluations []*big.Int
+	if evaluations, err = transport.Send(ModeCardinality, coefficients); err != nil {
+		return 0, err
+	}
+
+	var count = 0
+	for _, evaluation := range evaluations {
+		var value *big.Int
+		if value, err = client.decrypt(evaluation); err != nil {
+			return 0, err
+		} else if value.Sign() == 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// randomUnit returns a random number between 1 and N - 1.
+func (server *Server) randomUnit() (*big.Int, error) {
+	var r, err = rand.Int(rand.Reader, new(big.Int).Sub(server.PubKey.N, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	return r.Add(r, big.NewInt(1)), nil
+}
+
+// evaluate returns the encrypted evaluation of the polynomial with the
+// provided encrypted coefficients at the provided point, using the Horner
+// method with homomorphic operations.
+func (server *Server) evaluate(coefficients []*big.Int, point *big.Int) *big.Int {
+	var result = coefficients[len(coefficients)-1]
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		result = server.PubKey.AddEncrypted(server.PubKey.Mul(result, point), coefficients[i])
+	}
+	return result
+}
+
+// Function Handle evaluates the polynomial with the provided encrypted
+// coefficients at every element of the server set, randomizes the results
+// according to the provided mode and returns them shuffled. It returns an
+// error if the mode is not supported, if no coefficients are provided or if
+// the randomization fails.
+func (server *Server) Handle(mode string, coefficients []*big.Int) ([]*big.Int, error) {
+	if mode != ModeIntersection && mode != ModeCardinality {
+		return nil, fmt.Errorf("unsupported mode '%s'", mode)
+	} else if len(coefficients) < 2 {
+		return nil, errors.New("the polynomial must have at least degree one")
+	}
+
+	var evaluations = make([]*big.Int, len(server.hashes))
+	for i, h := range server.hashes {
+		var r, err = server.randomUnit()
+		if err != nil {
+			return nil, err
+		}
+
+		var noise *big.Int
+		if noise, err = server.PubKey.Encrypt(big.NewInt(0)); err != nil {
+			return nil, err
+		}
+
+		// E(r * P(y)) + E(0) [+ y]
+		var evaluation = server.PubKey.Mul(server.evaluate(coefficients, h), r)
+		evaluation = server.PubKey.AddEncrypted(evaluation, noise)
+		if mode == ModeIntersection {
+			evaluation = server.PubKey.Add(evaluation, h)
+		}
+		evaluations[i] = evaluation
+	}
+
+	// shuffle the evaluations using Fisher-Yates
+	for i := len(evaluations) - 1; i > 0; i-- {
+		var j, err = rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, err
+		}
+		evaluations[i], evaluations[j.Int64()] = evaluations[j.Int64()], evaluations[i]
+	}
+	return evaluations, nil
+}
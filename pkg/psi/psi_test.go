@@ -0,0 +1,69 @@
+package psi
+
+import (
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+var key, _ = paillier.NewKeys(128)
+
+var clientSet = []string{"alice@example.com", "bob@example.com", "carol@example.com", "dave@example.com"}
+var serverSet = []string{"erin@example.com", "carol@example.com", "alice@example.com", "frank@example.com", "carol@example.com"}
+
+func TestIntersection(t *testing.T) {
+	var client, err = NewClient(key, clientSet)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var transport = &MemoryTransport{Server: NewServer(key.PubKey, serverSet)}
+	var intersection []string
+	if intersection, err = client.Intersection(transport); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	sort.Strings(intersection)
+	if len(intersection) != 2 || intersection[0] != "alice@example.com" || intersection[1] != "carol@example.com" {
+		t.Fatalf("expected [alice@example.com carol@example.com], got %v", intersection)
+	}
+}
+
+func TestCardinality(t *testing.T) {
+	var client, _ = NewClient(key, clientSet)
+	var tests = []struct {
+		set      []string
+		expected int
+	}{
+		{serverSet, 2},
+		{[]string{"zoe@example.com"}, 0},
+		{clientSet, 4},
+		{[]string{}, 0},
+	}
+
+	for _, test := range tests {
+		var transport = &MemoryTransport{Server: NewServer(key.PubKey, test.set)}
+		if count, err := client.Cardinality(transport); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if count != test.expected {
+			t.Fatalf("expected %d, got %d", test.expected, count)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewClient(key, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var server = NewServer(key.PubKey, serverSet)
+	var client, _ = NewClient(key, clientSet)
+	var coefficients, _ = client.Coefficients()
+	if _, err := server.Handle("unknown", coefficients); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = server.Handle(ModeCardinality, []*big.Int{big.NewInt(1)}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,125 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+// Function InitClientContext works as InitClient but checks the provided
+// context during the key generation, returning ctx.Err() if it is cancelled
+// before the keys are generated.
+func InitClientContext(ctx context.Context, keySize int) (*Client, error) {
+	var key, err = paillier.NewKeysContext(ctx, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(key, key.PubKey), nil
+}
+
+// Function EncryptBatch returns the encrypted version of every provided
+// number.Number, checking the provided context before every encryption. It
+// returns ctx.Err() if the context is cancelled before all the inputs are
+// encrypted, or an error if any encryption fails.
+func (client *Client) EncryptBatch(ctx context.Context, nums []*number.Number) ([]*number.Number, error) {
+	var results = make([]*number.Number, len(nums))
+	for i, num := range nums {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if results[i], err = client.Encrypt(num); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// Function DecryptBatch returns the decrypted version of every provided
+// number.Number, checking the provided context before every decryption. It
+// returns ctx.Err() if the context is cancelled before all the inputs are
+// decrypted, or an error if any decryption fails.
+func (client *Client) DecryptBatch(ctx context.Context, nums []*number.Number) ([]*number.Number, error) {
+	var results = make([]*number.Number, len(nums))
+	for i, num := range nums {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if results[i], err = client.Decrypt(num); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// batch applies the provided operation to every pair of inputs of the same
+// position, checking the provided context before every operation.
+func batch(ctx context.Context, key PublicKey, op func(PublicKey, *number.Number, *number.Number) (*number.Number, error), a, b []*number.Number) ([]*number.Number, error) {
+	if len(a) != len(b) {
+		return nil, errors.New("both batches must have the same length")
+	}
+
+	var results = make([]*number.Number, len(a))
+	for i := range a {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if results[i], err = op(key, a[i], b[i]); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// Function AddEncryptedBatch computes AddEncrypted between every pair of
+// encrypted number.Number inputs of the same position, checking the provided
+// context before every operation. It returns ctx.Err() if the context is
+// cancelled, or an error if both batches have different lengths or if any
+// operation fails.
+func AddEncryptedBatch(ctx context.Context, key PublicKey, a, b []*number.Number) ([]*number.Number, error) {
+	return batch(ctx, key, AddEncrypted, a, b)
+}
+
+// Function AddBatch computes Add between every encrypted number.Number and the
+// plain number.Number of the same position, checking the provided context
+// before every operation. It returns ctx.Err() if the context is cancelled,
+// or an error if both batches have different lengths or if any operation
+// fails.
+func AddBatch(ctx context.Context, key PublicKey, encrypted, inputs []*number.Number) ([]*number.Number, error) {
+	return batch(ctx, key, Add, encrypted, inputs)
+}
+
+// Function SubBatch computes Sub between every encrypted number.Number and the
+// plain number.Number of the same position, checking the provided context
+// before every operation. It returns ctx.Err() if the context is cancelled,
+// or an error if both batches have different lengths or if any operation
+// fails.
+func SubBatch(ctx context.Context, key PublicKey, encrypted, inputs []*number.Number) ([]*number.Number, error) {
+	return batch(ctx, key, Sub, encrypted, inputs)
+}
+
+// Function MulBatch computes Mul between every encrypted number.Number and the
+// plain number.Number of the same position, checking the provided context
+// before every operation. It returns ctx.Err() if the context is cancelled,
+// or an error if both batches have different lengths or if any operation
+// fails.
+func MulBatch(ctx context.Context, key PublicKey, encrypted, inputs []*number.Number) ([]*number.Number, error) {
+	return batch(ctx, key, Mul, encrypted, inputs)
+}
+
+// Function DivBatch computes Div between every encrypted number.Number and the
+// plain number.Number of the same position, checking the provided context
+// before every operation. It returns ctx.Err() if the context is cancelled,
+// or an error if both batches have different lengths or if any operation
+// fails.
+func DivBatch(ctx context.Context, key PublicKey, encrypted, inputs []*number.Number) ([]*number.Number, error) {
+	return batch(ctx, key, Div, encrypted, inputs)
+}
@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+)
+
+func TestInitClientContext(t *testing.T) {
+	if _, err := InitClientContext(context.Background(), 64); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := InitClientContext(ctx, 2048); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	}
+}
+
+func TestBatchOperations(t *testing.T) {
+	var ctx = context.Background()
+	var a, b = []*number.Number{}, []*number.Number{}
+	for _, value := range []float64{1.5, -2, 10} {
+		var num, _ = new(number.Number).SetFloat(value)
+		a = append(a, num)
+	}
+	for _, value := range []float64{0.5, 4, -0.25} {
+		var num, _ = new(number.Number).SetFloat(value)
+		b = append(b, num)
+	}
+
+	var encryptedA, err = client.EncryptBatch(ctx, a)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	var encryptedB, _ = client.EncryptBatch(ctx, b)
+
+	var tests = []struct {
+		name     string
+		results  func() ([]*number.Number, error)
+		expected []float64
+	}{
+		{"add encrypted", func() ([]*number.Number, error) { return AddEncryptedBatch(ctx, client.PubKey, encryptedA, encryptedB) }, []float64{2, 2, 9.75}},
+		{"add", func() ([]*number.Number, error) { return AddBatch(ctx, client.PubKey, encryptedA, b) }, []float64{2, 2, 9.75}},
+		{"sub", func() ([]*number.Number, error) { return SubBatch(ctx, client.PubKey, encryptedA, b) }, []float64{1, -6, 10.25}},
+		{"mul", func() ([]*number.Number, error) { return MulBatch(ctx, client.PubKey, encryptedA, b) }, []float64{0.75, -8, -2.5}},
+		{"div", func() ([]*number.Number, error) { return DivBatch(ctx, client.PubKey, encryptedA, b) }, []float64{3, -0.5, -40}},
+	}
+
+	for _, test := range tests {
+		var results, err = test.results()
+		if err != nil {
+			t.Fatalf("%s: expected nil, got %s", test.name, err)
+		}
+
+		var decrypted []*number.Number
+		if decrypted, err = client.DecryptBatch(ctx, results); err != nil {
+			t.Fatalf("%s: expected nil, got %s", test.name, err)
+		}
+		for i, expected := range test.expected {
+			if decrypted[i].Float() != expected {
+				t.Fatalf("%s: expected %f, got %f", test.name, expected, decrypted[i].Float())
+			}
+		}
+	}
+
+	if _, err = AddBatch(ctx, client.PubKey, encryptedA, b[:2]); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = client.DecryptBatch(ctx, a); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var cancelled, cancel = context.WithCancel(ctx)
+	cancel()
+	if _, err = client.EncryptBatch(cancelled, a); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	} else if _, err = client.DecryptBatch(cancelled, encryptedA); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	} else if _, err = MulBatch(cancelled, client.PubKey, encryptedA, b); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	}
+}
@@ -1,31 +1,54 @@
 // Package SDK allows to interact with paillier package easily, allowing to use
 // floating point and integer numbers with it, and supporting more operations
-// such as subtraction and division over encrypted numbers.
+// such as subtraction and division over encrypted numbers. Any other additively
+// homomorphic cryptosystem could be used instead of Paillier if its keys
+// satisfy the sdk.PublicKey and sdk.PrivateKey interfaces.
 package sdk
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/lucasmenendez/gopaillier/pkg/number"
 	"github.com/lucasmenendez/gopaillier/pkg/paillier"
 )
 
-// Struct Client contains a Paillier key pair allowing to encrypt and decrypt
-// number.Number instances. Sharing Client.Key.PubKey (a paillier.PublicKey
-// instance) with an external actor, it could compute operations over a
-// number.Number encrypted with the same paillier.PublicKey.
+// Struct Client contains a key pair of an additively homomorphic cryptosystem
+// allowing to encrypt and decrypt number.Number instances. Sharing
+// Client.PubKey with an external actor, it could compute operations over a
+// number.Number encrypted with the same PublicKey. If KeyID is defined, it is
+// assigned to every encrypted number.Number, and if Keys is defined, it is
+// used to find the PrivateKey that matches the KeyID of the numbers to
+// decrypt, allowing to decrypt numbers encrypted with rotated keys.
 type Client struct {
-	Key *paillier.PrivateKey
+	Key    PrivateKey
+	PubKey PublicKey
+	KeyID  string
+	Keys   KeyResolver
+}
+
+// Interface KeyResolver defines how to find the PrivateKey identified by a key
+// identifier, for example from a key store.
+type KeyResolver interface {
+	PrivateKey(id string) (PrivateKey, error)
 }
 
 // Function InitClient returns a new client with a generated paillier.PrivKey
 // and paillier.PubKet pair with the size provided.
 func InitClient(keySize int) (*Client, error) {
-	var err error
-	var client = &Client{}
+	var key, err = paillier.NewKeys(keySize)
+	if err != nil {
+		return nil, err
+	}
 
-	client.Key, err = paillier.NewKeys(keySize)
-	return client, err
+	return NewClient(key, key.PubKey), nil
+}
+
+// Function NewClient returns a new client with the provided key pair, allowing
+// to use any cryptosystem that satisfies the PrivateKey and PublicKey
+// interfaces.
+func NewClient(key PrivateKey, pubKey PublicKey) *Client {
+	return &Client{Key: key, PubKey: pubKey}
 }
 
 // Function Encrypt returns the encrypted version of the provided number.Number.
@@ -38,20 +61,36 @@ func (client *Client) Encrypt(num *number.Number) (*number.Number, error) {
 
 	var err error
 	var result = new(number.Number).SetEncrypted(num)
-	result.Value, err = client.Key.PubKey.Encrypt(num.Value)
+	result.KeyID = client.KeyID
+	result.Value, err = client.PubKey.Encrypt(num.Value)
 	return result, err
 }
 
 // Function Decrypt returns the decrypted version of the provided number.Number.
-// It returns an error if the provided input is not encrypted or if some error
+// If the number has a key identifier different from the Client KeyID, the key
+// is obtained from the Client Keys. It returns an error if the provided input
+// is not encrypted, if the key to decrypt it is not available or if some error
 // occurs during the input decryption process.
 func (client *Client) Decrypt(num *number.Number) (*number.Number, error) {
 	if !num.IsEncrypted() {
 		return nil, errors.New("provided number is not encrypted")
 	}
 
+	var key = client.Key
+	if num.KeyID != "" && num.KeyID != client.KeyID {
+		if client.Keys == nil {
+			return nil, fmt.Errorf("key '%s' not available", num.KeyID)
+		}
+
+		var err error
+		if key, err = client.Keys.PrivateKey(num.KeyID); err != nil {
+			return nil, err
+		}
+	}
+
 	var err error
 	var result = new(number.Number).Set(num)
-	result.Value, err = client.Key.Decrypt(num.Value)
+	result.KeyID = ""
+	result.Value, err = key.Decrypt(num.Value)
 	return result, err
 }
@@ -2,9 +2,11 @@ package sdk
 
 import (
 	"crypto/rand"
+	"errors"
 	"math/big"
 	"testing"
 
+	"github.com/lucasmenendez/gopaillier/pkg/elgamal"
 	"github.com/lucasmenendez/gopaillier/pkg/number"
 )
 
@@ -32,7 +34,7 @@ func TestInitClient(t *testing.T) {
 
 func TestEncryptDecrypt(t *testing.T) {
 	var a float64 = -1223.1056
-	var encodedA = new(number.Number).SetFloat(a)
+	var encodedA, _ = new(number.Number).SetFloat(a)
 
 	var b int64 = 1209345
 	var encodedB = new(number.Number).SetInt(b)
@@ -71,3 +73,130 @@ func TestEncryptDecrypt(t *testing.T) {
 		t.Fatalf("expected nil, got %s", err)
 	}
 }
+
+// mockKey implements both PublicKey and PrivateKey interfaces without any
+// encryption, just adding a known offset to every plaintext, to check that
+// sdk works with any cryptosystem.
+type mockKey struct {
+	offset *big.Int
+}
+
+func (key *mockKey) Encrypt(input *big.Int) (*big.Int, error) {
+	return new(big.Int).Add(input, key.offset), nil
+}
+
+func (key *mockKey) Decrypt(input *big.Int) (*big.Int, error) {
+	return new(big.Int).Sub(input, key.offset), nil
+}
+
+func (key *mockKey) AddEncrypted(a, b *big.Int) *big.Int {
+	return new(big.Int).Sub(new(big.Int).Add(a, b), key.offset)
+}
+
+func (key *mockKey) Add(a, b *big.Int) *big.Int {
+	return new(big.Int).Add(a, b)
+}
+
+func (key *mockKey) Mul(a, b *big.Int) *big.Int {
+	var plain = new(big.Int).Sub(a, key.offset)
+	return new(big.Int).Add(plain.Mul(plain, b), key.offset)
+}
+
+func TestNewClient(t *testing.T) {
+	var key = &mockKey{big.NewInt(1000)}
+	var client = NewClient(key, key)
+
+	var a, _ = new(number.Number).SetFloat(-12.5)
+	var b = new(number.Number).SetInt(4)
+	var encryptedA, err = client.Encrypt(a)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var encryptedSum, _ = Add(client.PubKey, encryptedA, b)
+	var encryptedMul, _ = Mul(client.PubKey, encryptedSum, b)
+	if result, err := client.Decrypt(encryptedMul); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if result.Float() != -34 {
+		t.Fatalf("expected -34, got %v", result.Float())
+	}
+}
+
+func TestElGamalClient(t *testing.T) {
+	var key, _ = elgamal.NewKeys(1000000)
+	var client = NewClient(key, key.PubKey)
+
+	var a, _ = new(number.Number).SetFloat(12.5)
+	var b, _ = new(number.Number).SetFloat(-0.25)
+	var encryptedA, err = client.Encrypt(a)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var encryptedSum, _ = Add(client.PubKey, encryptedA, b)
+	var encryptedMul, _ = Mul(client.PubKey, encryptedSum, b)
+	if result, err := client.Decrypt(encryptedMul); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if result.Float() != -3.0625 {
+		t.Fatalf("expected -3.0625, got %v", result.Float())
+	}
+
+	// malformed ciphertexts return an error instead of panicking
+	var malformed = new(number.Number).SetEncrypted(&number.Number{Value: big.NewInt(12345), Exp: big.NewInt(0)})
+	if _, err = Add(client.PubKey, malformed, b); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = Mul(client.PubKey, malformed, b); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = Div(client.PubKey, malformed, b); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = AddEncrypted(client.PubKey, encryptedA, malformed); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = AddEncrypted(client.PubKey, malformed, encryptedSum); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+type mockResolver map[string]PrivateKey
+
+func (resolver mockResolver) PrivateKey(id string) (PrivateKey, error) {
+	if key, ok := resolver[id]; ok {
+		return key, nil
+	}
+	return nil, errors.New("key not found")
+}
+
+func TestKeyID(t *testing.T) {
+	var oldKey, newKey = &mockKey{big.NewInt(1000)}, &mockKey{big.NewInt(2000)}
+	var oldClient = NewClient(oldKey, oldKey)
+	oldClient.KeyID = "old"
+	var client = NewClient(newKey, newKey)
+	client.KeyID = "new"
+
+	var oldEncrypted, _ = oldClient.Encrypt(new(number.Number).SetInt(5))
+	var newEncrypted, _ = client.Encrypt(new(number.Number).SetInt(3))
+	if oldEncrypted.KeyID != "old" || newEncrypted.KeyID != "new" {
+		t.Fatalf("expected old and new, got %s and %s", oldEncrypted.KeyID, newEncrypted.KeyID)
+	}
+
+	// the operations keep the key identifier
+	var result, _ = Mul(oldClient.PubKey, oldEncrypted, new(number.Number).SetInt(2))
+	if result, _ = Sub(oldClient.PubKey, result, new(number.Number).SetInt(1)); result.KeyID != "old" {
+		t.Fatalf("expected old, got %s", result.KeyID)
+	} else if _, err := AddEncrypted(client.PubKey, oldEncrypted, newEncrypted); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// without resolver the numbers of other keys can not be decrypted
+	if _, err := client.Decrypt(result); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	client.Keys = mockResolver{"old": oldKey}
+	if decrypted, err := client.Decrypt(result); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if decrypted.Int() != 9 {
+		t.Fatalf("expected 9, got %d", decrypted.Int())
+	} else if decrypted, err = client.Decrypt(newEncrypted); err != nil || decrypted.Int() != 3 {
+		t.Fatalf("expected 3, got %v", err)
+	}
+}
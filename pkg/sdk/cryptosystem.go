@@ -0,0 +1,33 @@
+package sdk
+
+import (
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+// Interface PublicKey defines the operations that an additively homomorphic
+// cryptosystem must provide to be used by the sdk package: encrypt big.Int
+// plaintexts, add two ciphertexts, add a plaintext to a ciphertext and
+// multiply a ciphertext by a plaintext scalar. Both plaintext inputs and
+// decrypted outputs could be negative, so the cryptosystem must map them into
+// its plaintext space. The operations over ciphertexts must return nil if any
+// of the ciphertexts provided is not valid. paillier.PublicKey satisfies it.
+type PublicKey interface {
+	Encrypt(input *big.Int) (*big.Int, error)
+	AddEncrypted(a, b *big.Int) *big.Int
+	Add(a, b *big.Int) *big.Int
+	Mul(a, b *big.Int) *big.Int
+}
+
+// Interface PrivateKey defines the decryption operation that the private key
+// of an additively homomorphic cryptosystem must provide to be used by the sdk
+// package. paillier.PrivateKey satisfies it.
+type PrivateKey interface {
+	Decrypt(input *big.Int) (*big.Int, error)
+}
+
+// Ensure that the paillier package keys satisfy the sdk interfaces. The rest
+// of backends check it in their own tests, to not import them here.
+var _ PublicKey = (*paillier.PublicKey)(nil)
+var _ PrivateKey = (*paillier.PrivateKey)(nil)
@@ -5,7 +5,6 @@ import (
 	"math/big"
 
 	"github.com/lucasmenendez/gopaillier/pkg/number"
-	"github.com/lucasmenendez/gopaillier/pkg/paillier"
 )
 
 func checkArgs(encrypted, plain *number.Number) error {
@@ -18,25 +17,71 @@ func checkArgs(encrypted, plain *number.Number) error {
 	return nil
 }
 
+// encryptedResult returns the provided result as an encrypted number.Number.
+// It returns an error if the result has no value, because the PublicKey
+// returns nil when any of the ciphertexts provided is not valid.
+func encryptedResult(result *number.Number) (*number.Number, error) {
+	if result.Value == nil {
+		return nil, errors.New("invalid ciphertext provided")
+	}
+	return new(number.Number).SetEncrypted(result), nil
+}
+
+// Function AddEncrypted computes the addition of both encrypted number.Number
+// inputs using the provided PublicKey. If both inputs have different
+// Number.Exp, the one with the greatest exponent is scaled using homomorphic
+// multiplication before perform the addition. It returns an error if any of
+// the inputs is not encrypted or if they have different key identifiers.
+func AddEncrypted(key PublicKey, a, b *number.Number) (*number.Number, error) {
+	if !a.IsEncrypted() || !b.IsEncrypted() {
+		return nil, errors.New("both Numbers provided must be encrypted")
+	} else if a.KeyID != "" && b.KeyID != "" && a.KeyID != b.KeyID {
+		return nil, errors.New("both Numbers provided must be encrypted with the same key")
+	}
+
+	var result = &number.Number{KeyID: a.KeyID}
+	if result.KeyID == "" {
+		result.KeyID = b.KeyID
+	}
+	if cmp := a.Exp.Cmp(b.Exp); cmp == 0 {
+		result.Exp = a.Exp
+		result.Value = key.AddEncrypted(a.Value, b.Value)
+	} else {
+		var expDiff = new(big.Int).Abs(new(big.Int).Sub(a.Exp, b.Exp))
+		var factor = new(big.Int).Exp(big.NewInt(10), expDiff, nil)
+		if cmp > 0 {
+			result.Exp = b.Exp
+			var normalized = key.Mul(a.Value, factor)
+			result.Value = key.AddEncrypted(normalized, b.Value)
+		} else {
+			result.Exp = a.Exp
+			var normalized = key.Mul(b.Value, factor)
+			result.Value = key.AddEncrypted(a.Value, normalized)
+		}
+	}
+
+	return encryptedResult(result)
+}
+
 // Function Add computes the addition of the encrypted number.Number and plain
-// number.Number inputs using the provided paillier.PublicKey. It transform the
+// number.Number inputs using the provided PublicKey. It transform the
 // number with the greatest Number.Exp and scale its num.Value to normalize it
 // with the input number.Number, and then perform de addition. If the greatest
-// exponent is not from encrypted number.Number it scale using Paillier
+// exponent is not from encrypted number.Number it scale using homomorphic
 // multiplication. It returns an error if the encrypted number.Number is not
 // encrypted or if the input number.Number is encrypted.
-func Add(key *paillier.PublicKey, encrypted, input *number.Number) (*number.Number, error) {
+func Add(key PublicKey, encrypted, input *number.Number) (*number.Number, error) {
 	if err := checkArgs(encrypted, input); err != nil {
 		return nil, err
 	}
 
 	// Instance the result to store the computed Number.Exp and Number.Value.
-	var result = new(number.Number)
+	var result = &number.Number{KeyID: encrypted.KeyID}
 
-	// Compare encrypted.Exp and input.Exp, if both are equals, perform Paillier
-	// addition using the provided paillier.PublicKey. If not, transform one of
+	// Compare encrypted.Exp and input.Exp, if both are equals, perform homomorphic
+	// addition using the provided PublicKey. If not, transform one of
 	// the inputs to ensure that both have the same Number.Exp. If the
-	// transformation will be applied over encrypted input it will use Paillier
+	// transformation will be applied over encrypted input it will use homomorphic
 	// operations.
 	if cmp := encrypted.Exp.Cmp(input.Exp); cmp == 0 {
 		result.Exp = encrypted.Exp
@@ -55,7 +100,7 @@ func Add(key *paillier.PublicKey, encrypted, input *number.Number) (*number.Numb
 		}
 	}
 
-	return new(number.Number).SetEncrypted(result), nil
+	return encryptedResult(result)
 }
 
 // Function Sub computes the subtraction of the encrypted number.Number and the
@@ -64,7 +109,7 @@ func Add(key *paillier.PublicKey, encrypted, input *number.Number) (*number.Numb
 // of between it and the encrypted number.Number. It returns an error if the
 // encrypted number.Number is not encrypted or if the input number.Number is
 // encrypted.
-func Sub(key *paillier.PublicKey, encrypted, input *number.Number) (*number.Number, error) {
+func Sub(key PublicKey, encrypted, input *number.Number) (*number.Number, error) {
 	if err := checkArgs(encrypted, input); err != nil {
 		return nil, err
 	}
@@ -77,19 +122,19 @@ func Sub(key *paillier.PublicKey, encrypted, input *number.Number) (*number.Numb
 
 // Function Mul computes the multiplication between the encrypted number.Number
 // and the input number.Number provided. To perform the operation calculates the
-// Paillier multiplication between encrypted.Value and input.Value, and then
+// homomorphic multiplication between encrypted.Value and input.Value, and then
 // calculates the plain addition between encrypted.Exp and input.Exp. It returns
 // an error if the encrypted number.Number is not encrypted or if the input
 // number.Number is encrypted.
-func Mul(key *paillier.PublicKey, encrypted, input *number.Number) (*number.Number, error) {
+func Mul(key PublicKey, encrypted, input *number.Number) (*number.Number, error) {
 	if err := checkArgs(encrypted, input); err != nil {
 		return nil, err
 	}
 
-	var result = new(number.Number)
+	var result = &number.Number{KeyID: encrypted.KeyID}
 	result.Value = key.Mul(encrypted.Value, input.Value)
 	result.Exp = new(big.Int).Add(encrypted.Exp, input.Exp)
-	return new(number.Number).SetEncrypted(result), nil
+	return encryptedResult(result)
 }
 
 // Function Div computes the division of the encrypted number.Number and the
@@ -98,12 +143,16 @@ func Mul(key *paillier.PublicKey, encrypted, input *number.Number) (*number.Numb
 // of between it and the encrypted number.Number. It returns an error if the
 // encrypted number.Number is not encrypted or if the input number.Number is
 // encrypted.
-func Div(key *paillier.PublicKey, encrypted, input *number.Number) (*number.Number, error) {
+func Div(key PublicKey, encrypted, input *number.Number) (*number.Number, error) {
 	if err := checkArgs(encrypted, input); err != nil {
 		return nil, err
 	}
 
 	var inverse = 1 / input.Float()
-	var invInput = new(number.Number).SetFloat(inverse)
+	var invInput, err = new(number.Number).SetFloat(inverse)
+	if err != nil {
+		return nil, err
+	}
+
 	return Mul(key, encrypted, invInput)
 }
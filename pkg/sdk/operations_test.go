@@ -12,43 +12,43 @@ var c, d int64 = 2340, 12200023
 
 var client, _ = InitClient(128)
 
-var encodedA = new(number.Number).SetFloat(a)
+var encodedA, _ = new(number.Number).SetFloat(a)
 var encryptedA, _ = client.Encrypt(encodedA)
-var encodedB = new(number.Number).SetFloat(b)
+var encodedB, _ = new(number.Number).SetFloat(b)
 var encryptedB, _ = client.Encrypt(encodedB)
 var encodedC = new(number.Number).SetInt(c)
 var encryptedC, _ = client.Encrypt(encodedC)
 var encodedD = new(number.Number).SetInt(d)
 
 func TestAdd(t *testing.T) {
-	if _, err := Add(client.Key.PubKey, encodedA, encodedB); err == nil {
+	if _, err := Add(client.PubKey, encodedA, encodedB); err == nil {
 		t.Fatal("expected error, got nil")
-	} else if _, err = Add(client.Key.PubKey, encryptedA, encryptedB); err == nil {
+	} else if _, err = Add(client.PubKey, encryptedA, encryptedB); err == nil {
 		t.Fatal("expected error, got nil")
 	}
 
-	var encryptedSumAB, _ = Add(client.Key.PubKey, encryptedA, encodedB)
+	var encryptedSumAB, _ = Add(client.PubKey, encryptedA, encodedB)
 	var decryptedSumAB, _ = client.Decrypt(encryptedSumAB)
 	var rawSumAB = fmt.Sprintf("%f", a+b)
 	if sResult := fmt.Sprintf("%f", decryptedSumAB.Float()); rawSumAB != sResult {
 		t.Fatalf("expected %s, got %s", rawSumAB, sResult)
 	}
 
-	var encryptedSumCD, _ = Add(client.Key.PubKey, encryptedC, encodedD)
+	var encryptedSumCD, _ = Add(client.PubKey, encryptedC, encodedD)
 	var decryptedSumCD, _ = client.Decrypt(encryptedSumCD)
 	var rawSumCD = fmt.Sprintf("%d", c+d)
 	if sResult := fmt.Sprintf("%d", decryptedSumCD.Int()); rawSumCD != sResult {
 		t.Fatalf("expected %s, got %s", rawSumCD, sResult)
 	}
 
-	var encryptedSumAC, _ = Add(client.Key.PubKey, encryptedA, encodedC)
+	var encryptedSumAC, _ = Add(client.PubKey, encryptedA, encodedC)
 	var decryptedSumAC, _ = client.Decrypt(encryptedSumAC)
 	var rawSumAC = fmt.Sprintf("%f", a+float64(c))
 	if sResult := fmt.Sprintf("%f", decryptedSumAC.Float()); rawSumAC != sResult {
 		t.Fatalf("expected %s, got %s", rawSumAC, sResult)
 	}
 
-	var encryptedSumBD, _ = Add(client.Key.PubKey, encryptedB, encodedD)
+	var encryptedSumBD, _ = Add(client.PubKey, encryptedB, encodedD)
 	var decryptedSumBD, _ = client.Decrypt(encryptedSumBD)
 	var rawSumBD = fmt.Sprintf("%f", b+float64(d))
 	if sResult := fmt.Sprintf("%f", decryptedSumBD.Float()); rawSumBD != sResult {
@@ -57,34 +57,34 @@ func TestAdd(t *testing.T) {
 }
 
 func TestSub(t *testing.T) {
-	if _, err := Sub(client.Key.PubKey, encodedA, encodedB); err == nil {
+	if _, err := Sub(client.PubKey, encodedA, encodedB); err == nil {
 		t.Fatal("expected error, got nil")
-	} else if _, err = Sub(client.Key.PubKey, encryptedA, encryptedB); err == nil {
+	} else if _, err = Sub(client.PubKey, encryptedA, encryptedB); err == nil {
 		t.Fatal("expected error, got nil")
 	}
 
-	var encryptedDiffAB, _ = Sub(client.Key.PubKey, encryptedA, encodedB)
+	var encryptedDiffAB, _ = Sub(client.PubKey, encryptedA, encodedB)
 	var decryptedDiffAB, _ = client.Decrypt(encryptedDiffAB)
 	var rawDiffAB = fmt.Sprintf("%f", a-b)
 	if sResult := fmt.Sprintf("%f", decryptedDiffAB.Float()); rawDiffAB != sResult {
 		t.Fatalf("expected %s, got %s", rawDiffAB, sResult)
 	}
 
-	var encryptedDiffCD, _ = Sub(client.Key.PubKey, encryptedC, encodedD)
+	var encryptedDiffCD, _ = Sub(client.PubKey, encryptedC, encodedD)
 	var decryptedDiffCD, _ = client.Decrypt(encryptedDiffCD)
 	var rawDiffCD = fmt.Sprintf("%d", c-d)
 	if sResult := fmt.Sprintf("%d", decryptedDiffCD.Int()); rawDiffCD != sResult {
 		t.Fatalf("expected %s, got %s", rawDiffCD, sResult)
 	}
 
-	var encryptedDiffAC, _ = Sub(client.Key.PubKey, encryptedA, encodedC)
+	var encryptedDiffAC, _ = Sub(client.PubKey, encryptedA, encodedC)
 	var decryptedDiffAC, _ = client.Decrypt(encryptedDiffAC)
 	var rawDiffAC = fmt.Sprintf("%f", a-float64(c))
 	if sResult := fmt.Sprintf("%f", decryptedDiffAC.Float()); rawDiffAC != sResult {
 		t.Fatalf("expected %s, got %s", rawDiffAC, sResult)
 	}
 
-	var encryptedDiffBD, _ = Sub(client.Key.PubKey, encryptedB, encodedD)
+	var encryptedDiffBD, _ = Sub(client.PubKey, encryptedB, encodedD)
 	var decryptedDiffBD, _ = client.Decrypt(encryptedDiffBD)
 	var rawDiffBD = fmt.Sprintf("%f", b-float64(d))
 	if sResult := fmt.Sprintf("%f", decryptedDiffBD.Float()); rawDiffBD != sResult {
@@ -93,34 +93,34 @@ func TestSub(t *testing.T) {
 }
 
 func TestMul(t *testing.T) {
-	if _, err := Mul(client.Key.PubKey, encodedA, encodedB); err == nil {
+	if _, err := Mul(client.PubKey, encodedA, encodedB); err == nil {
 		t.Fatal("expected error, got nil")
-	} else if _, err = Mul(client.Key.PubKey, encryptedA, encryptedB); err == nil {
+	} else if _, err = Mul(client.PubKey, encryptedA, encryptedB); err == nil {
 		t.Fatal("expected error, got nil")
 	}
 
-	var encryptedMulAB, _ = Mul(client.Key.PubKey, encryptedA, encodedB)
+	var encryptedMulAB, _ = Mul(client.PubKey, encryptedA, encodedB)
 	var decryptedMulAB, _ = client.Decrypt(encryptedMulAB)
 	var rawMullAB = fmt.Sprintf("%f", a*b)
 	if sResult := fmt.Sprintf("%f", decryptedMulAB.Float()); rawMullAB != sResult {
 		t.Fatalf("expected %s, got %s", rawMullAB, sResult)
 	}
 
-	var encryptedMulCD, _ = Mul(client.Key.PubKey, encryptedC, encodedD)
+	var encryptedMulCD, _ = Mul(client.PubKey, encryptedC, encodedD)
 	var decryptedMulCD, _ = client.Decrypt(encryptedMulCD)
 	var rawMullCD = fmt.Sprintf("%d", c*d)
 	if sResult := fmt.Sprintf("%d", decryptedMulCD.Int()); rawMullCD != sResult {
 		t.Fatalf("expected %s, got %s", rawMullCD, sResult)
 	}
 
-	var encryptedMulAC, _ = Mul(client.Key.PubKey, encryptedA, encodedC)
+	var encryptedMulAC, _ = Mul(client.PubKey, encryptedA, encodedC)
 	var decryptedMulAC, _ = client.Decrypt(encryptedMulAC)
 	var rawMullAC = fmt.Sprintf("%f", a*float64(c))
 	if sResult := fmt.Sprintf("%f", decryptedMulAC.Float()); rawMullAC != sResult {
 		t.Fatalf("expected %s, got %s", rawMullAC, sResult)
 	}
 
-	var encryptedMulBD, _ = Mul(client.Key.PubKey, encryptedB, encodedD)
+	var encryptedMulBD, _ = Mul(client.PubKey, encryptedB, encodedD)
 	var decryptedMulBD, _ = client.Decrypt(encryptedMulBD)
 	var rawMullBD = fmt.Sprintf("%f", b*float64(d))
 	if sResult := fmt.Sprintf("%f", decryptedMulBD.Float()); rawMullBD != sResult {
@@ -129,37 +129,59 @@ func TestMul(t *testing.T) {
 }
 
 func TestDiv(t *testing.T) {
-	if _, err := Div(client.Key.PubKey, encodedA, encodedB); err == nil {
+	if _, err := Div(client.PubKey, encodedA, encodedB); err == nil {
 		t.Fatal("expected error, got nil")
-	} else if _, err = Div(client.Key.PubKey, encryptedA, encryptedB); err == nil {
+	} else if _, err = Div(client.PubKey, encryptedA, encryptedB); err == nil {
 		t.Fatal("expected error, got nil")
 	}
 
-	var encryptedDivAB, _ = Div(client.Key.PubKey, encryptedA, encodedB)
+	var encryptedDivAB, _ = Div(client.PubKey, encryptedA, encodedB)
 	var decryptedDivAB, _ = client.Decrypt(encryptedDivAB)
 	var rawDivlAB = fmt.Sprintf("%f", a/b)
 	if sResult := fmt.Sprintf("%f", decryptedDivAB.Float()); rawDivlAB != sResult {
 		t.Fatalf("expected %s, got %s", rawDivlAB, sResult)
 	}
 
-	var encryptedDivCD, _ = Div(client.Key.PubKey, encryptedC, encodedD)
+	var encryptedDivCD, _ = Div(client.PubKey, encryptedC, encodedD)
 	var decryptedDivCD, _ = client.Decrypt(encryptedDivCD)
 	var rawDivlCD = fmt.Sprintf("%d", c/d)
 	if sResult := fmt.Sprintf("%d", decryptedDivCD.Int()); rawDivlCD != sResult {
 		t.Fatalf("expected %s, got %s", rawDivlCD, sResult)
 	}
 
-	var encryptedDivAC, _ = Div(client.Key.PubKey, encryptedA, encodedC)
+	var encryptedDivAC, _ = Div(client.PubKey, encryptedA, encodedC)
 	var decryptedDivAC, _ = client.Decrypt(encryptedDivAC)
 	var rawDivlAC = fmt.Sprintf("%f", a/float64(c))
 	if sResult := fmt.Sprintf("%f", decryptedDivAC.Float()); rawDivlAC != sResult {
 		t.Fatalf("expected %s, got %s", rawDivlAC, sResult)
 	}
 
-	var encryptedDivBD, _ = Div(client.Key.PubKey, encryptedB, encodedD)
+	var encryptedDivBD, _ = Div(client.PubKey, encryptedB, encodedD)
 	var decryptedDivBD, _ = client.Decrypt(encryptedDivBD)
 	var rawDivlBD = fmt.Sprintf("%f", b/float64(d))
 	if sResult := fmt.Sprintf("%f", decryptedDivBD.Float()); rawDivlBD != sResult {
 		t.Fatalf("expected %s, got %s", rawDivlBD, sResult)
 	}
 }
+
+func TestAddEncrypted(t *testing.T) {
+	if _, err := AddEncrypted(client.PubKey, encryptedA, encodedB); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = AddEncrypted(client.PubKey, encodedA, encryptedB); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var encryptedSumAB, _ = AddEncrypted(client.PubKey, encryptedA, encryptedB)
+	var decryptedSumAB, _ = client.Decrypt(encryptedSumAB)
+	var rawSumAB = fmt.Sprintf("%f", a+b)
+	if sResult := fmt.Sprintf("%f", decryptedSumAB.Float()); rawSumAB != sResult {
+		t.Fatalf("expected %s, got %s", rawSumAB, sResult)
+	}
+
+	var encryptedSumCA, _ = AddEncrypted(client.PubKey, encryptedC, encryptedA)
+	var decryptedSumCA, _ = client.Decrypt(encryptedSumCA)
+	var rawSumCA = fmt.Sprintf("%f", float64(c)+a)
+	if sResult := fmt.Sprintf("%f", decryptedSumCA.Float()); rawSumCA != sResult {
+		t.Fatalf("expected %s, got %s", rawSumCA, sResult)
+	}
+}
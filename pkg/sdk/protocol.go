@@ -0,0 +1,530 @@
+package sdk
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+)
+
+// DefaultMaskBits is the default size in bits of the random masks used by the
+// Evaluator to blind the encrypted values before send them to the KeyHolder.
+// The masks must be much larger than the blinded values to hide them
+// statistically, and the plaintext space of the key must be large enough to
+// store the operations results over the blinded values.
+const DefaultMaskBits = 80
+
+// Operations supported by the KeyHolder.
+const (
+	// OpMul requests the KeyHolder to decrypt two blinded values, multiply
+	// them and return the encrypted result.
+	OpMul = "mul"
+	// OpSign requests the KeyHolder to decrypt a blinded value and return the
+	// encrypted bit [value > 0].
+	OpSign = "sign"
+	// OpRevealSign requests the KeyHolder to decrypt a blinded value and return
+	// the plain bit [value > 0]. The KeyHolder can not tell a blinded value
+	// from any other ciphertext, so it acts as a sign oracle, see KeyHolder.
+	OpRevealSign = "reveal-sign"
+	// OpSqrt requests the KeyHolder to decrypt a blinded non-negative value
+	// and return the encryption of its square root.
+	OpSqrt = "sqrt"
+	// OpReencrypt requests the KeyHolder to decrypt blinded values and return
+	// them encrypted under the PublicKey of its Target.
+	OpReencrypt = "reencrypt"
+)
+
+// Interface Transport defines how the Evaluator sends requests to the
+// KeyHolder, allowing to implement it over any communication channel. The
+// request includes the operation to perform and its number.Number inputs,
+// and it returns the number.Number results of the operation.
+type Transport interface {
+	Send(op string, inputs []*number.Number) ([]*number.Number, error)
+}
+
+// Struct MemoryTransport implements the Transport interface calling directly
+// the KeyHolder provided, for testing or for cases where both actors run in
+// the same process.
+type MemoryTransport struct {
+	Holder *KeyHolder
+}
+
+// Function Send sends the operation and its inputs to the KeyHolder and
+// returns its results.
+func (transport *MemoryTransport) Send(op string, inputs []*number.Number) ([]*number.Number, error) {
+	return transport.Holder.Handle(op, inputs)
+}
+
+// Struct KeyHolder contains the Client with the private key, and attends the
+// requests of the interactive protocols sent by an Evaluator, that only know
+// the public key. The KeyHolder only decrypts blinded values, so it does not
+// learn the original values. If Target is defined, the KeyHolder also
+// re-encrypts blinded values under the Target PublicKey, assigning them the
+// Target KeyID, to move them to a new key. The Target Client only requires
+// the PublicKey.
+//
+// The KeyHolder can not check that the inputs have been blinded, so it
+// answers any ciphertext: an Evaluator that sends E(x - c) for chosen values
+// of c to OpSign or OpRevealSign learns the comparison of x with c, and it can
+// recover any plaintext x with a binary search. The KeyHolder must only
+// attend Evaluators that it trusts to follow the protocols, authenticating
+// and limiting their requests in the Transport.
+type KeyHolder struct {
+	Client *Client
+	Target *Client
+}
+
+// Function NewKeyHolder returns a new KeyHolder with the provided Client.
+func NewKeyHolder(client *Client) *KeyHolder {
+	return &KeyHolder{Client: client}
+}
+
+// Function Handle performs the operation requested over the inputs provided
+// and returns the results. It returns an error if the operation is not
+// supported, if the inputs are not valid for it or if any error occurs during
+// the operation.
+func (holder *KeyHolder) Handle(op string, inputs []*number.Number) ([]*number.Number, error) {
+	switch op {
+	case OpMul:
+		return holder.mul(inputs)
+	case OpSign:
+		return holder.sign(inputs, true)
+	case OpRevealSign:
+		return holder.sign(inputs, false)
+	case OpSqrt:
+		return holder.sqrt(inputs)
+	case OpReencrypt:
+		return holder.reencrypt(inputs)
+	default:
+		return nil, fmt.Errorf("unknown operation '%s'", op)
+	}
+}
+
+// mul decrypts both inputs provided, multiply them and returns the encrypted
+// result.
+func (holder *KeyHolder) mul(inputs []*number.Number) ([]*number.Number, error) {
+	if len(inputs) != 2 {
+		return nil, errors.New("multiplication requires two inputs")
+	}
+
+	var x, y, result *number.Number
+	var err error
+	if x, err = holder.Client.Decrypt(inputs[0]); err != nil {
+		return nil, err
+	} else if y, err = holder.Client.Decrypt(inputs[1]); err != nil {
+		return nil, err
+	} else if result, err = new(number.Number).Mul(x, y); err != nil {
+		return nil, err
+	} else if result, err = holder.Client.Encrypt(result); err != nil {
+		return nil, err
+	}
+
+	return []*number.Number{result}, nil
+}
+
+// sign decrypts the input provided and returns the bit [value > 0], encrypted
+// or not depending on the encrypt argument.
+func (holder *KeyHolder) sign(inputs []*number.Number, encrypt bool) ([]*number.Number, error) {
+	if len(inputs) != 1 {
+		return nil, errors.New("sign requires one input")
+	}
+
+	var value, err = holder.Client.Decrypt(inputs[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var result = new(number.Number).SetInt(0)
+	if value.Value.Sign() > 0 {
+		result = new(number.Number).SetInt(1)
+	}
+
+	if encrypt {
+		if result, err = holder.Client.Encrypt(result); err != nil {
+			return nil, err
+		}
+	}
+	return []*number.Number{result}, nil
+}
+
+// sqrt decrypts the input provided and returns the encryption of its square
+// root, computed as float64.
+func (holder *KeyHolder) sqrt(inputs []*number.Number) ([]*number.Number, error) {
+	if len(inputs) != 1 {
+		return nil, errors.New("sqrt requires one input")
+	}
+
+	var value, err = holder.Client.Decrypt(inputs[0])
+	if err != nil {
+		return nil, err
+	} else if value.Value.Sign() < 0 {
+		return nil, errors.New("sqrt requires a non-negative input")
+	}
+
+	var result *number.Number
+	if result, err = new(number.Number).SetFloat(math.Sqrt(value.Float())); err != nil {
+		return nil, err
+	} else if result, err = holder.Client.Encrypt(result); err != nil {
+		return nil, err
+	}
+	return []*number.Number{result}, nil
+}
+
+// reencrypt decrypts every input provided and returns them encrypted under
+// the Target PublicKey.
+func (holder *KeyHolder) reencrypt(inputs []*number.Number) ([]*number.Number, error) {
+	if holder.Target == nil {
+		return nil, errors.New("re-encryption target key not defined")
+	} else if len(inputs) == 0 {
+		return nil, errors.New("re-encryption requires at least one input")
+	}
+
+	var results = make([]*number.Number, len(inputs))
+	for i, input := range inputs {
+		var value, err = holder.Client.Decrypt(input)
+		if err != nil {
+			return nil, err
+		} else if results[i], err = holder.Target.Encrypt(value); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// Struct Evaluator contains the PublicKey of the KeyHolder and the Transport
+// to communicate with it, allowing to perform operations over encrypted
+// number.Number's that are not supported by the homomorphic properties of the
+// cryptosystem, with the help of the KeyHolder. MaskBits defines the size of
+// the masks used to blind the values sent to the KeyHolder.
+type Evaluator struct {
+	PubKey    PublicKey
+	Transport Transport
+	MaskBits  int
+}
+
+// Function NewEvaluator returns a new Evaluator with the provided PublicKey and
+// Transport, using DefaultMaskBits as the size of the masks.
+func NewEvaluator(pubKey PublicKey, transport Transport) *Evaluator {
+	return &Evaluator{pubKey, transport, DefaultMaskBits}
+}
+
+// mask returns a new random plain number.Number with the exponent provided
+// and a value lower than 2^MaskBits.
+func (evaluator *Evaluator) mask(exp *big.Int) (*number.Number, error) {
+	var max = new(big.Int).Lsh(big.NewInt(1), uint(evaluator.MaskBits))
+	var value, err = rand.Int(rand.Reader, max)
+	if err != nil {
+		return nil, err
+	}
+
+	return &number.Number{Value: value, Exp: new(big.Int).Set(exp)}, nil
+}
+
+// Function Mul computes the multiplication of both encrypted number.Number's
+// provided with the help of the KeyHolder, following the next protocol:
+//  1. The Evaluator blinds both inputs with random masks rx and ry:
+//     E(x + rx) and E(y + ry), and sends them to the KeyHolder.
+//  2. The KeyHolder decrypts them, computes (x + rx) * (y + ry) and returns
+//     its encrypted version.
+//  3. The Evaluator removes the masks homomorphically:
+//     E(x * y) = E((x + rx) * (y + ry)) - rx * E(y) - ry * E(x) - rx * ry
+//
+// The exponent of the result is the addition of both input exponents. It
+// returns an error if any of the inputs is not encrypted or if the
+// communication with the KeyHolder fails.
+func (evaluator *Evaluator) Mul(a, b *number.Number) (*number.Number, error) {
+	if !a.IsEncrypted() || !b.IsEncrypted() {
+		return nil, errors.New("both Numbers provided must be encrypted")
+	}
+
+	// Blind both inputs with random masks with the same exponent.
+	var key = evaluator.PubKey
+	var rx, ry, blindX, blindY *number.Number
+	var err error
+	if rx, err = evaluator.mask(a.Exp); err != nil {
+		return nil, err
+	} else if ry, err = evaluator.mask(b.Exp); err != nil {
+		return nil, err
+	} else if blindX, err = Add(key, a, rx); err != nil {
+		return nil, err
+	} else if blindY, err = Add(key, b, ry); err != nil {
+		return nil, err
+	}
+
+	var results []*number.Number
+	if results, err = evaluator.Transport.Send(OpMul, []*number.Number{blindX, blindY}); err != nil {
+		return nil, err
+	} else if len(results) != 1 || !results[0].IsEncrypted() {
+		return nil, errors.New("unexpected response from the KeyHolder")
+	}
+
+	// Remove the masks: -rx * E(y), -ry * E(x) and -rx * ry.
+	var negRx, _ = new(number.Number).Neg(rx)
+	var negRy, _ = new(number.Number).Neg(ry)
+	var rxy, _ = new(number.Number).Mul(negRx, ry)
+
+	var result, term *number.Number
+	if term, err = Mul(key, b, negRx); err != nil {
+		return nil, err
+	} else if result, err = AddEncrypted(key, results[0], term); err != nil {
+		return nil, err
+	} else if term, err = Mul(key, a, negRy); err != nil {
+		return nil, err
+	} else if result, err = AddEncrypted(key, result, term); err != nil {
+		return nil, err
+	}
+
+	return Add(key, result, rxy)
+}
+
+// blindedDiff computes the encrypted difference d = a - b between the
+// encrypted number.Number a and the encrypted or plain number.Number b, with
+// both exponents aligned, and blinds it with random values preserving its
+// sign, where:
+//
+//	z = s * (r * d + r'), with 0 < r' < r and s ∈ {-1, 1}
+//
+// Because r' < r, z > 0 if and only if d >= 0 and s = 1, or d < 0 and s = -1.
+// It returns the encrypted blinded value and if s is negative. It returns an
+// error if a is not encrypted or if the key provides its plaintext size and
+// there is no room for the masks of MaskBits into it.
+func (evaluator *Evaluator) blindedDiff(a, b *number.Number) (*number.Number, bool, error) {
+	if !a.IsEncrypted() {
+		return nil, false, errors.New("first Number provided must be encrypted")
+	}
+
+	var key = evaluator.PubKey
+	if bounded, ok := key.(interface{ PlaintextBits() int }); ok && bounded.PlaintextBits() <= evaluator.MaskBits+1 {
+		return nil, false, fmt.Errorf("masks of %d bits do not fit into the %d bits of the plaintexts of the key",
+			evaluator.MaskBits, bounded.PlaintextBits())
+	}
+
+	var diff *number.Number
+	var err error
+	if b.IsEncrypted() {
+		var negB *number.Number
+		if negB, err = Mul(key, b, new(number.Number).SetInt(-1)); err != nil {
+			return nil, false, err
+		} else if diff, err = AddEncrypted(key, a, negB); err != nil {
+			return nil, false, err
+		}
+	} else if diff, err = Sub(key, a, b); err != nil {
+		return nil, false, err
+	}
+
+	// Generate r in [2, 2^MaskBits), r' in [1, r) and the sign s.
+	var max = new(big.Int).Lsh(big.NewInt(1), uint(evaluator.MaskBits))
+	var r, rl, s *big.Int
+	if r, err = rand.Int(rand.Reader, new(big.Int).Sub(max, big.NewInt(2))); err != nil {
+		return nil, false, err
+	}
+	r.Add(r, big.NewInt(2))
+
+	if rl, err = rand.Int(rand.Reader, new(big.Int).Sub(r, big.NewInt(1))); err != nil {
+		return nil, false, err
+	} else if s, err = rand.Int(rand.Reader, big.NewInt(2)); err != nil {
+		return nil, false, err
+	}
+	rl.Add(rl, big.NewInt(1))
+
+	var negative = s.Sign() == 0
+	if negative {
+		r.Neg(r)
+		rl.Neg(rl)
+	}
+
+	// Compute z = s * r * d + s * r', where s * r' has the exponent of d.
+	var blinded *number.Number
+	var sr = &number.Number{Value: r, Exp: big.NewInt(0)}
+	var srl = &number.Number{Value: rl, Exp: new(big.Int).Set(diff.Exp)}
+	if blinded, err = Mul(key, diff, sr); err != nil {
+		return nil, false, err
+	} else if blinded, err = Add(key, blinded, srl); err != nil {
+		return nil, false, err
+	}
+	return blinded, negative, nil
+}
+
+// Function GreaterEqual computes the encrypted comparison bit [a >= b] of the
+// encrypted number.Number a and the encrypted or plain number.Number b, with
+// the help of the KeyHolder, following the next protocol:
+//  1. The Evaluator computes E(d) = E(a - b), aligning the exponents of both
+//     inputs, and blinds it preserving its sign up to a random flip s:
+//     E(z) = E(s * (r * d + r')), with 0 < r' < r and s ∈ {-1, 1}.
+//  2. The KeyHolder decrypts z and returns E([z > 0]). It does not learn the
+//     comparison result, because it does not know s.
+//  3. The Evaluator undoes the flip homomorphically: if s = -1, the result is
+//     E(1 - [z > 0]), and re-randomizes the result.
+//
+// The blinded value must fit into the signed plaintext range of the key, so
+// (|a - b| + 1) * 2^MaskBits must be lower than the half of it, that is,
+// |a - b| with the exponents aligned must be lower than
+// 2^(PlaintextBits - MaskBits - 1) for the keys that provide PlaintextBits.
+// The bound can not be checked over encrypted inputs, so the caller must
+// ensure it, otherwise the result is wrong. Only the room for the masks into
+// the plaintexts of the key is checked. The KeyHolder answers the sign of any
+// ciphertext, so it must trust the Evaluator, read more in KeyHolder. The
+// result is an encrypted number.Number with value 1 if a >= b or 0 otherwise.
+// It returns an error if a is not encrypted, if the masks do not fit into the
+// plaintexts of the key or if the communication with the KeyHolder fails.
+func (evaluator *Evaluator) GreaterEqual(a, b *number.Number) (*number.Number, error) {
+	var blinded, negative, err = evaluator.blindedDiff(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*number.Number
+	if results, err = evaluator.Transport.Send(OpSign, []*number.Number{blinded}); err != nil {
+		return nil, err
+	} else if len(results) != 1 || !results[0].IsEncrypted() {
+		return nil, errors.New("unexpected response from the KeyHolder")
+	}
+
+	var key = evaluator.PubKey
+	var result = results[0]
+	if negative {
+		if result, err = Mul(key, result, new(number.Number).SetInt(-1)); err != nil {
+			return nil, err
+		} else if result, err = Add(key, result, new(number.Number).SetInt(1)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Re-randomize the result adding a fresh encryption of zero, so the
+	// KeyHolder can not link it with its response.
+	var zero = new(number.Number).SetInt(0)
+	if zero.Value, err = key.Encrypt(zero.Value); err != nil {
+		return nil, err
+	}
+	return AddEncrypted(key, result, new(number.Number).SetEncrypted(zero))
+}
+
+// Function RevealGreaterEqual returns if the encrypted number.Number a is
+// greater or equal than the encrypted or plain number.Number b, with the help
+// of the KeyHolder, following the same protocol as Evaluator.GreaterEqual, but
+// receiving a plain bit from the KeyHolder, with the same input bound and
+// trust assumption. Only the Evaluator learns the result. It returns an error
+// if a is not encrypted, if the masks do not fit into the plaintexts of the
+// key or if the communication with the KeyHolder fails.
+func (evaluator *Evaluator) RevealGreaterEqual(a, b *number.Number) (bool, error) {
+	var blinded, negative, err = evaluator.blindedDiff(a, b)
+	if err != nil {
+		return false, err
+	}
+
+	var results []*number.Number
+	if results, err = evaluator.Transport.Send(OpRevealSign, []*number.Number{blinded}); err != nil {
+		return false, err
+	} else if len(results) != 1 || results[0].IsEncrypted() {
+		return false, errors.New("unexpected response from the KeyHolder")
+	}
+
+	var positive = results[0].Value.Sign() > 0
+	return positive != negative, nil
+}
+
+// Function Sqrt computes the square root of the encrypted non-negative
+// number.Number provided with the help of the KeyHolder, following the next
+// protocol:
+//  1. The Evaluator blinds the input multiplying it by the square of a random
+//     mask r and adding a random noise s lower than r: E(x * r^2 + s), and
+//     sends it to the KeyHolder.
+//  2. The KeyHolder decrypts it and returns the encryption of its square root
+//     E(sqrt(x * r^2 + s)), that is close to E(sqrt(x) * r).
+//  3. The Evaluator removes the mask homomorphically dividing by r.
+//
+// Without the noise, the KeyHolder would learn the square-free part of x,
+// that is the same as the one of x * r^2. The noise hides it, but it also
+// changes the input by less than 10^exp / r, where exp is the exponent of the
+// input. The KeyHolder still learns the order of magnitude of x up to the
+// factor r^2. The size of r is the half of MaskBits, with its highest bit set,
+// and the square root is computed using float64 precision. It returns an error
+// if the input is not encrypted, if MaskBits is lower than 4 or if the
+// communication with the KeyHolder fails.
+func (evaluator *Evaluator) Sqrt(a *number.Number) (*number.Number, error) {
+	if !a.IsEncrypted() {
+		return nil, errors.New("provided Number must be encrypted")
+	} else if evaluator.MaskBits < 4 {
+		return nil, errors.New("the masks must have at least 4 bits")
+	}
+
+	// Generate r in [2^(k-1), 2^k), with k = MaskBits / 2, and s in [0, r).
+	var min = new(big.Int).Lsh(big.NewInt(1), uint(evaluator.MaskBits/2-1))
+	var r, s *big.Int
+	var err error
+	if r, err = rand.Int(rand.Reader, min); err != nil {
+		return nil, err
+	} else if s, err = rand.Int(rand.Reader, r.Add(r, min)); err != nil {
+		return nil, err
+	}
+
+	var key = evaluator.PubKey
+	var mask = &number.Number{Value: r, Exp: big.NewInt(0)}
+	var squaredMask, _ = new(number.Number).Mul(mask, mask)
+
+	var blinded *number.Number
+	if blinded, err = Mul(key, a, squaredMask); err != nil {
+		return nil, err
+	} else if blinded, err = Add(key, blinded, &number.Number{Value: s, Exp: new(big.Int).Set(a.Exp)}); err != nil {
+		return nil, err
+	}
+
+	var results []*number.Number
+	if results, err = evaluator.Transport.Send(OpSqrt, []*number.Number{blinded}); err != nil {
+		return nil, err
+	} else if len(results) != 1 || !results[0].IsEncrypted() {
+		return nil, errors.New("unexpected response from the KeyHolder")
+	}
+
+	return Div(key, results[0], mask)
+}
+
+// Function Reencrypt moves the encrypted number.Number's provided from the
+// PublicKey of the KeyHolder to the target PublicKey provided, without
+// decrypting them in the clear, following the next protocol:
+//  1. The Evaluator blinds every input with a random mask r with its same
+//     exponent: E_old(x + r), and sends them to the KeyHolder.
+//  2. The KeyHolder decrypts them and returns E_new(x + r), encrypted under
+//     the PublicKey of its Target.
+//  3. The Evaluator removes the masks homomorphically under the target key:
+//     E_new(x) = E_new(x + r) - r
+//
+// The exponents of the inputs are preserved, and the results get the KeyID
+// assigned by the KeyHolder Target. The target PublicKey must be the same as
+// the KeyHolder Target one. It returns an error if any of the inputs is not
+// encrypted or if the communication with the KeyHolder fails.
+func (evaluator *Evaluator) Reencrypt(target PublicKey, inputs []*number.Number) ([]*number.Number, error) {
+	var masks = make([]*number.Number, len(inputs))
+	var blinded = make([]*number.Number, len(inputs))
+	for i, input := range inputs {
+		if !input.IsEncrypted() {
+			return nil, errors.New("all the Numbers provided must be encrypted")
+		}
+
+		var err error
+		if masks[i], err = evaluator.mask(input.Exp); err != nil {
+			return nil, err
+		} else if blinded[i], err = Add(evaluator.PubKey, input, masks[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var results, err = evaluator.Transport.Send(OpReencrypt, blinded)
+	if err != nil {
+		return nil, err
+	} else if len(results) != len(inputs) {
+		return nil, errors.New("unexpected response from the KeyHolder")
+	}
+
+	for i, result := range results {
+		if !result.IsEncrypted() || result.Exp.Cmp(masks[i].Exp) != 0 {
+			return nil, errors.New("unexpected response from the KeyHolder")
+		} else if results[i], err = Sub(target, result, masks[i]); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,194 @@
+package sdk
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+)
+
+var evaluator = NewEvaluator(client.PubKey, &MemoryTransport{NewKeyHolder(client)})
+
+func TestEvaluatorMul(t *testing.T) {
+	if _, err := evaluator.Mul(encryptedA, encodedB); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = evaluator.Mul(encodedA, encryptedB); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var encryptedMulAB, err = evaluator.Mul(encryptedA, encryptedB)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	var decryptedMulAB, _ = client.Decrypt(encryptedMulAB)
+	var rawMulAB = fmt.Sprintf("%f", a*b)
+	if sResult := fmt.Sprintf("%f", decryptedMulAB.Float()); rawMulAB != sResult {
+		t.Fatalf("expected %s, got %s", rawMulAB, sResult)
+	}
+
+	var encryptedMulCC, _ = evaluator.Mul(encryptedC, encryptedC)
+	var decryptedMulCC, _ = client.Decrypt(encryptedMulCC)
+	if result := decryptedMulCC.Int(); result != c*c {
+		t.Fatalf("expected %d, got %d", c*c, result)
+	}
+
+	var encodedZero = new(number.Number).SetInt(0)
+	var encryptedZero, _ = client.Encrypt(encodedZero)
+	var encryptedMulZero, _ = evaluator.Mul(encryptedB, encryptedZero)
+	var decryptedMulZero, _ = client.Decrypt(encryptedMulZero)
+	if result := decryptedMulZero.Float(); result != 0 {
+		t.Fatalf("expected 0, got %f", result)
+	}
+}
+
+func TestKeyHolderHandle(t *testing.T) {
+	var holder = NewKeyHolder(client)
+	if _, err := holder.Handle("unknown", []*number.Number{encryptedA}); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = holder.Handle(OpMul, []*number.Number{encryptedA}); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = holder.Handle(OpMul, []*number.Number{encryptedA, encodedB}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestEvaluatorGreaterEqual(t *testing.T) {
+	if _, err := evaluator.GreaterEqual(encodedA, encodedB); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var encodedA2, _ = new(number.Number).SetFloat(1890.05214)
+	var encryptedA2, _ = client.Encrypt(encodedA2)
+	var pairs = [][2]*number.Number{
+		{encryptedA, encodedB}, {encryptedB, encodedA}, {encryptedA, encryptedB},
+		{encryptedB, encryptedA}, {encryptedA, encodedA}, {encryptedA, encryptedA},
+		{encryptedA, encodedA2}, {encryptedA2, encryptedA}, {encryptedC, encodedD},
+		{encryptedC, encodedC},
+	}
+	var expected = []bool{true, false, true, false, true, true, false, true, false, true}
+
+	// repeat the checks to cover both values of the random flip
+	for i := 0; i < 4; i++ {
+		for j, pair := range pairs {
+			var encryptedBit, err = evaluator.GreaterEqual(pair[0], pair[1])
+			if err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+
+			var bit, _ = client.Decrypt(encryptedBit)
+			if result := bit.Int() == 1; result != expected[j] {
+				t.Fatalf("pair %d: expected %t, got %t", j, expected[j], result)
+			}
+
+			if result, err := evaluator.RevealGreaterEqual(pair[0], pair[1]); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			} else if result != expected[j] {
+				t.Fatalf("pair %d: expected %t, got %t", j, expected[j], result)
+			}
+		}
+	}
+}
+
+func TestEvaluatorGreaterEqualMaskBound(t *testing.T) {
+	var pubKey = client.PubKey.(interface{ PlaintextBits() int })
+	var large = NewEvaluator(client.PubKey, &MemoryTransport{NewKeyHolder(client)})
+	large.MaskBits = pubKey.PlaintextBits() - 1
+	if _, err := large.GreaterEqual(encryptedA, encodedB); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = large.RevealGreaterEqual(encryptedA, encodedB); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestEvaluatorSqrt(t *testing.T) {
+	if _, err := evaluator.Sqrt(encodedA); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = evaluator.Sqrt(encryptedB); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var encryptedSqrtA, err = evaluator.Sqrt(encryptedA)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	var decryptedSqrtA, _ = client.Decrypt(encryptedSqrtA)
+	var rawSqrtA = fmt.Sprintf("%.6f", math.Sqrt(a))
+	if sResult := fmt.Sprintf("%.6f", decryptedSqrtA.Float()); rawSqrtA != sResult {
+		t.Fatalf("expected %s, got %s", rawSqrtA, sResult)
+	}
+
+	// the KeyHolder must not receive a perfect square when the input is one,
+	// because it would reveal the square-free part of the input
+	var spy = &spyTransport{Transport: &MemoryTransport{NewKeyHolder(client)}}
+	var encryptedFour, _ = client.Encrypt(new(number.Number).SetInt(4))
+	for i := 0; i < 10; i++ {
+		if _, err = NewEvaluator(client.PubKey, spy).Sqrt(encryptedFour); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		var blinded, _ = client.Decrypt(spy.inputs[0])
+		if root := new(big.Int).Sqrt(blinded.Value); root.Mul(root, root).Cmp(blinded.Value) == 0 {
+			t.Fatalf("expected a blinded value that is not a perfect square, got %s", blinded.Value)
+		}
+	}
+
+	var small = NewEvaluator(client.PubKey, spy)
+	small.MaskBits = 2
+	if _, err = small.Sqrt(encryptedFour); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// spyTransport stores the inputs of the last request sent through the
+// Transport.
+type spyTransport struct {
+	Transport
+	inputs []*number.Number
+}
+
+func (spy *spyTransport) Send(op string, inputs []*number.Number) ([]*number.Number, error) {
+	spy.inputs = inputs
+	return spy.Transport.Send(op, inputs)
+}
+
+func TestEvaluatorReencrypt(t *testing.T) {
+	var target, _ = InitClient(512)
+	target.KeyID = "new"
+	var holder = NewKeyHolder(client)
+	var switcher = NewEvaluator(client.PubKey, &MemoryTransport{holder})
+	if _, err := switcher.Reencrypt(target.PubKey, []*number.Number{encryptedA}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	holder.Target = &Client{PubKey: target.PubKey, KeyID: target.KeyID}
+	if _, err := switcher.Reencrypt(target.PubKey, []*number.Number{encryptedA, encodedB}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var negative, _ = client.Encrypt(new(number.Number).SetInt(-c))
+	var inputs = []*number.Number{encryptedA, encryptedB, negative}
+	var results, err = switcher.Reencrypt(target.PubKey, inputs)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var expected = []float64{a, b, float64(-c)}
+	for i, result := range results {
+		if result.KeyID != "new" {
+			t.Fatalf("expected key id 'new', got '%s'", result.KeyID)
+		} else if result.Exp.Cmp(inputs[i].Exp) != 0 {
+			t.Fatalf("expected exponent %d, got %d", inputs[i].Exp, result.Exp)
+		} else if _, err = client.Decrypt(result); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		var decrypted, err = target.Decrypt(result)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if fmt.Sprintf("%f", decrypted.Float()) != fmt.Sprintf("%f", expected[i]) {
+			t.Fatalf("expected %f, got %f", expected[i], decrypted.Float())
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package stats allows to compute descriptive statistics over encrypted
+// number.Number's without decrypting them: the sum, the mean, the weighted
+// mean, the variance and the standard deviation. The sum and the means only
+// require the public key, using the homomorphic properties of the
+// cryptosystem. The variance and the standard deviation require the help of
+// the keyholder through an sdk.Evaluator, which never decrypts the original
+// values, only blinded ones. All the results are encrypted and returned with
+// the number of values used to compute them. The division of the means, the
+// variance and the standard deviation can not be computed exactly over
+// encrypted values, so they are returned as a Quotient, with an encrypted
+// numerator and a plain denominator, that is divided exactly after decrypting
+// the numerator.
+package stats
+
+import (
+	"errors"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// DefaultDecimals is the default number of decimals used to round the
+// divisions of the Quotient's that have no exact decimal representation.
+const DefaultDecimals = 16
+
+// Struct Quotient contains the encrypted Numerator and the plain Denominator
+// of a division over encrypted values, such as a mean. The division is
+// computed after decrypting the Numerator, so it is exact.
+type Quotient struct {
+	Numerator   *number.Number
+	Denominator *number.Number
+}
+
+// Function Decrypt decrypts the Numerator of the current Quotient with the
+// provided sdk.Client and returns its division by the Denominator, exact if
+// it has no more decimals than the provided ones or rounded to them
+// otherwise. It returns an error if the decryption fails or if the
+// Denominator is zero.
+func (quotient *Quotient) Decrypt(client *sdk.Client, decimals int) (*number.Number, error) {
+	var numerator, err = client.Decrypt(quotient.Numerator)
+	if err != nil {
+		return nil, err
+	}
+	return new(number.Number).Quo(numerator, quotient.Denominator, decimals)
+}
+
+// Function Sum returns the encrypted addition of the encrypted number.Number's
+// provided and the number of values added, using the provided PublicKey. It
+// returns an error if no values are provided or if any of them is not
+// encrypted.
+func Sum(key sdk.PublicKey, values []*number.Number) (*number.Number, int, error) {
+	if len(values) == 0 {
+		return nil, 0, errors.New("no values provided")
+	}
+
+	var result = values[0]
+	if !result.IsEncrypted() {
+		return nil, 0, errors.New("all the values must be encrypted")
+	}
+
+	var err error
+	for _, value := range values[1:] {
+		if result, err = sdk.AddEncrypted(key, result, value); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return result, len(values), nil
+}
+
+// Function Mean returns the encrypted arithmetic mean of the encrypted
+// number.Number's provided as a Quotient, with the encrypted sum of the values
+// as numerator and the number of values as denominator, and the number of
+// values used. It returns an error if no values are provided or if any of
+// them is not encrypted.
+func Mean(key sdk.PublicKey, values []*number.Number) (*Quotient, int, error) {
+	var sum, count, err = Sum(key, values)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &Quotient{sum, new(number.Number).SetInt(int64(count))}, count, nil
+}
+
+// Function WeightedMean returns the encrypted weighted mean of the encrypted
+// number.Number's provided using the plain weights provided as a Quotient,
+// and the number of values used:
+//
+//	mean = Σ(w_i * x_i) / Σ(w_i)
+//
+// It returns an error if no values are provided, if the number of values and
+// weights is different, if any value is not encrypted, if any weight is
+// encrypted or if the weights add up to zero.
+func WeightedMean(key sdk.PublicKey, values, weights []*number.Number) (*Quotient, int, error) {
+	if len(values) != len(weights) {
+		return nil, 0, errors.New("the number of values and weights must be the same")
+	}
+
+	var weighted = make([]*number.Number, len(values))
+	var totalWeight = new(number.Number).SetInt(0)
+	var err error
+	for i, value := range values {
+		if weighted[i], err = sdk.Mul(key, value, weights[i]); err != nil {
+			return nil, 0, err
+		} else if totalWeight, err = new(number.Number).Add(totalWeight, weights[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var sum *number.Number
+	var count int
+	if sum, count, err = Sum(key, weighted); err != nil {
+		return nil, 0, err
+	} else if totalWeight.Value.Sign() == 0 {
+		return nil, 0, errors.New("the weights must not add up to zero")
+	}
+	return &Quotient{sum, totalWeight}, count, nil
+}
+
+// Function Variance returns the encrypted population variance of the
+// encrypted number.Number's provided as a Quotient, and the number of values
+// used. The squares of the values are computed with the help of the keyholder
+// through the provided sdk.Evaluator, which requires an interaction per value.
+// Use VarianceFromSquares if the encrypted squares are available. It returns
+// an error if no values are provided, if any of them is not encrypted or if
+// the communication with the keyholder fails.
+func Variance(evaluator *sdk.Evaluator, values []*number.Number) (*Quotient, int, error) {
+	var squares = make([]*number.Number, len(values))
+	var err error
+	for i, value := range values {
+		if squares[i], err = evaluator.Mul(value, value); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return VarianceFromSquares(evaluator, values, squares)
+}
+
+// Function VarianceFromSquares returns the encrypted population variance of
+// the encrypted number.Number's provided as a Quotient, and the number of
+// values used, using the encrypted squares of the values, that must be
+// computed and encrypted by the owners of the values:
+//
+//	var = (n * Σ(x_i^2) - (Σ(x_i))^2) / n^2
+//
+// The square of the sum is computed with the help of the keyholder through
+// the provided sdk.Evaluator. It returns an error if no values are provided,
+// if the number of values and squares is different, if any of them is not
+// encrypted or if the communication with the keyholder fails.
+func VarianceFromSquares(evaluator *sdk.Evaluator, values, squares []*number.Number) (*Quotient, int, error) {
+	if len(values) != len(squares) {
+		return nil, 0, errors.New("the number of values and squares must be the same")
+	}
+
+	var numerator, count, err = varianceNumerator(evaluator, values, squares)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var n = new(number.Number).SetInt(int64(count))
+	var denominator, _ = new(number.Number).Mul(n, n)
+	return &Quotient{numerator, denominator}, count, nil
+}
+
+// varianceNumerator returns the encrypted numerator of the population
+// variance, n * Σ(x_i^2) - (Σ(x_i))^2, and the number of values used.
+func varianceNumerator(evaluator *sdk.Evaluator, values, squares []*number.Number) (*number.Number, int, error) {
+	var key = evaluator.PubKey
+	var sum, sumSquares, squaredSum *number.Number
+	var count int
+	var err error
+	if sum, count, err = Sum(key, values); err != nil {
+		return nil, 0, err
+	} else if sumSquares, _, err = Sum(key, squares); err != nil {
+		return nil, 0, err
+	} else if squaredSum, err = evaluator.Mul(sum, sum); err != nil {
+		return nil, 0, err
+	} else if sumSquares, err = sdk.Mul(key, sumSquares, new(number.Number).SetInt(int64(count))); err != nil {
+		return nil, 0, err
+	}
+
+	var negSquaredSum, numerator *number.Number
+	if negSquaredSum, err = sdk.Mul(key, squaredSum, new(number.Number).SetInt(-1)); err != nil {
+		return nil, 0, err
+	} else if numerator, err = sdk.AddEncrypted(key, sumSquares, negSquaredSum); err != nil {
+		return nil, 0, err
+	}
+	return numerator, count, nil
+}
+
+// Function StdDev returns the encrypted population standard deviation of the
+// encrypted number.Number's provided as a Quotient, and the number of values
+// used:
+//
+//	std = sqrt(n * Σ(x_i^2) - (Σ(x_i))^2) / n
+//
+// The squares of the values and the square root are computed with the help of
+// the keyholder through the provided sdk.Evaluator, the last one with float64
+// precision.
+func StdDev(evaluator *sdk.Evaluator, values []*number.Number) (*Quotient, int, error) {
+	var squares = make([]*number.Number, len(values))
+	var err error
+	for i, value := range values {
+		if squares[i], err = evaluator.Mul(value, value); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return StdDevFromSquares(evaluator, values, squares)
+}
+
+// Function StdDevFromSquares returns the encrypted population standard
+// deviation of the encrypted number.Number's provided as a Quotient, and the
+// number of values used, using the encrypted squares of the values. The
+// square root is computed with the help of the keyholder through the provided
+// sdk.Evaluator, with float64 precision.
+func StdDevFromSquares(evaluator *sdk.Evaluator, values, squares []*number.Number) (*Quotient, int, error) {
+	if len(values) != len(squares) {
+		return nil, 0, errors.New("the number of values and squares must be the same")
+	}
+
+	var numerator, count, err = varianceNumerator(evaluator, values, squares)
+	if err != nil {
+		return nil, 0, err
+	} else if numerator, err = evaluator.Sqrt(numerator); err != nil {
+		return nil, 0, err
+	}
+	return &Quotient{numerator, new(number.Number).SetInt(int64(count))}, count, nil
+}
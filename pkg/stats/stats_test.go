@@ -0,0 +1,192 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+var client, _ = sdk.InitClient(512)
+var evaluator = sdk.NewEvaluator(client.PubKey, &sdk.MemoryTransport{Holder: sdk.NewKeyHolder(client)})
+
+var inputs = []float64{4.5, -2.25, 10, 7.125, 0, 3.5, 12.75}
+var weights = []float64{1, 2, 0.5, 3, 1, 1.5, 2}
+
+func encryptAll(t *testing.T, values []float64) []*number.Number {
+	var result = make([]*number.Number, len(values))
+	for i, value := range values {
+		var encoded, _ = new(number.Number).SetFloat(value)
+		var err error
+		if result[i], err = client.Encrypt(encoded); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	}
+	return result
+}
+
+func checkResult(t *testing.T, encrypted *number.Number, expected float64) {
+	var decrypted, err = client.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var sExpected = fmt.Sprintf("%.6f", expected)
+	if sResult := fmt.Sprintf("%.6f", decrypted.Float()); sResult != sExpected {
+		t.Fatalf("expected %s, got %s", sExpected, sResult)
+	}
+}
+
+func checkQuotient(t *testing.T, quotient *Quotient, expected float64) {
+	var decrypted, err = quotient.Decrypt(client, DefaultDecimals)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	var sExpected = fmt.Sprintf("%.6f", expected)
+	if sResult := fmt.Sprintf("%.6f", decrypted.Float()); sResult != sExpected {
+		t.Fatalf("expected %s, got %s", sExpected, sResult)
+	}
+}
+
+func rawStats() (sum, mean, variance float64) {
+	for _, input := range inputs {
+		sum += input
+	}
+	mean = sum / float64(len(inputs))
+	for _, input := range inputs {
+		variance += (input - mean) * (input - mean)
+	}
+	variance /= float64(len(inputs))
+	return
+}
+
+func TestSum(t *testing.T) {
+	if _, _, err := Sum(client.PubKey, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var plain, _ = new(number.Number).SetFloat(inputs[0])
+	if _, _, err := Sum(client.PubKey, []*number.Number{plain}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var sum, _, _ = rawStats()
+	var encryptedSum, count, err = Sum(client.PubKey, encryptAll(t, inputs))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if count != len(inputs) {
+		t.Fatalf("expected %d, got %d", len(inputs), count)
+	}
+	checkResult(t, encryptedSum, sum)
+}
+
+func TestMean(t *testing.T) {
+	var _, mean, _ = rawStats()
+	var encryptedMean, count, err = Mean(client.PubKey, encryptAll(t, inputs))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if count != len(inputs) {
+		t.Fatalf("expected %d, got %d", len(inputs), count)
+	}
+	checkQuotient(t, encryptedMean, mean)
+
+	// the division is computed after the decryption, so it is exact when the
+	// mean has a finite decimal representation
+	var tests = []struct {
+		values   []float64
+		expected string
+	}{
+		{[]float64{1, 1, 1}, "1"},
+		{[]float64{0.1, 0.2, 0.3}, "0.2"},
+		{[]float64{1, 2, 2}, "1.6666666666666667"},
+	}
+	for _, test := range tests {
+		var quotient, _, _ = Mean(client.PubKey, encryptAll(t, test.values))
+		if decrypted, err := quotient.Decrypt(client, DefaultDecimals); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if decrypted.String() != test.expected {
+			t.Fatalf("expected %s, got %s", test.expected, decrypted)
+		}
+	}
+}
+
+func TestWeightedMean(t *testing.T) {
+	var weighted, total float64
+	var encodedWeights = make([]*number.Number, len(weights))
+	for i, weight := range weights {
+		weighted += weight * inputs[i]
+		total += weight
+		encodedWeights[i], _ = new(number.Number).SetFloat(weight)
+	}
+
+	var encrypted = encryptAll(t, inputs)
+	var encryptedMean, count, err = WeightedMean(client.PubKey, encrypted, encodedWeights)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if count != len(inputs) {
+		t.Fatalf("expected %d, got %d", len(inputs), count)
+	}
+	checkQuotient(t, encryptedMean, weighted/total)
+
+	if _, _, err = WeightedMean(client.PubKey, encrypted, encodedWeights[1:]); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var zeros = []*number.Number{new(number.Number).SetInt(1), new(number.Number).SetInt(-1)}
+	if _, _, err = WeightedMean(client.PubKey, encrypted[:2], zeros); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestVariance(t *testing.T) {
+	var _, _, variance = rawStats()
+	var encrypted = encryptAll(t, inputs)
+
+	var encryptedVariance, count, err = Variance(evaluator, encrypted)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if count != len(inputs) {
+		t.Fatalf("expected %d, got %d", len(inputs), count)
+	}
+	checkQuotient(t, encryptedVariance, variance)
+
+	var squares = make([]float64, len(inputs))
+	for i, input := range inputs {
+		squares[i] = input * input
+	}
+	var encryptedSquares = encryptAll(t, squares)
+	if encryptedVariance, _, err = VarianceFromSquares(evaluator, encrypted, encryptedSquares); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	checkQuotient(t, encryptedVariance, variance)
+
+	if _, _, err = VarianceFromSquares(evaluator, encrypted, encryptedSquares[1:]); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	var _, _, variance = rawStats()
+	var encrypted = encryptAll(t, inputs)
+
+	var encryptedStdDev, count, err = StdDev(evaluator, encrypted)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if count != len(inputs) {
+		t.Fatalf("expected %d, got %d", len(inputs), count)
+	}
+	checkQuotient(t, encryptedStdDev, math.Sqrt(variance))
+
+	var squares = make([]float64, len(inputs))
+	for i, input := range inputs {
+		squares[i] = input * input
+	}
+	var encryptedSquares = encryptAll(t, squares)
+	if encryptedStdDev, _, err = StdDevFromSquares(evaluator, encrypted, encryptedSquares); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	checkQuotient(t, encryptedStdDev, math.Sqrt(variance))
+}
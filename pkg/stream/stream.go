@@ -0,0 +1,189 @@
+// Package stream allows to aggregate continuous streams of encrypted
+// number.Number's. An Aggregator consumes the encrypted numbers from an input
+// channel, groups them into windows and emits the encrypted sum of every
+// window into an output channel. The sums are computed as a binary tree of
+// homomorphic additions, where the additions of every level run in parallel
+// across a pool of workers, instead of folding the values one by one in a
+// single goroutine:
+//
+//	level 0:  a   b   c   d   e
+//	level 1:   a+b     c+d    e
+//	level 2:     a+b+c+d      e
+//	level 3:       a+b+c+d+e
+//
+// The windows are defined by number of values. Tumbling windows (Slide equal
+// to Size, or zero) do not overlap, while sliding windows (Slide lower than
+// Size) emit the sum of the last Size values every Slide values.
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+// Struct Aggregator contains the sdk.PublicKey used to add the encrypted
+// numbers, the number of parallel Workers and the Size and Slide, in number of
+// values, of the windows.
+type Aggregator struct {
+	PubKey  sdk.PublicKey
+	Workers int
+	Size    int
+	Slide   int
+}
+
+// Struct Result contains the encrypted sum of a window, the number of values
+// added, and the position into the stream of its first value. If the
+// aggregation fails, Err contains the error and the stream stops.
+type Result struct {
+	Sum   *number.Number
+	Count int
+	Start int
+	Err   error
+}
+
+// Function NewAggregator returns a new Aggregator with the provided
+// sdk.PublicKey, number of workers and window size and slide. A zero slide
+// defines tumbling windows. It returns an error if the number of workers or
+// the window size are not positive, or if the slide is negative or greater
+// than the size.
+func NewAggregator(pubKey sdk.PublicKey, workers, size, slide int) (*Aggregator, error) {
+	if workers < 1 {
+		return nil, errors.New("the number of workers must be positive")
+	} else if size < 1 {
+		return nil, errors.New("the window size must be positive")
+	} else if slide < 0 || slide > size {
+		return nil, errors.New("the window slide must be between 0 and the window size")
+	}
+
+	if slide == 0 {
+		slide = size
+	}
+	return &Aggregator{PubKey: pubKey, Workers: workers, Size: size, Slide: slide}, nil
+}
+
+// Function Sum returns the encrypted sum of the provided encrypted numbers,
+// adding them as a binary tree with the additions of every level distributed
+// across the workers of the current Aggregator. It returns an error if no
+// values are provided, if any of them is not encrypted or if the provided
+// context is cancelled.
+func (agg *Aggregator) Sum(ctx context.Context, values []*number.Number) (*number.Number, error) {
+	if len(values) == 0 {
+		return nil, errors.New("no values provided")
+	}
+
+	var level = append([]*number.Number{}, values...)
+	for len(level) > 1 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var next = make([]*number.Number, (len(level)+1)/2)
+		var pairs = make(chan int)
+		var mtx sync.Mutex
+		var firstErr error
+		var wg sync.WaitGroup
+		for w := 0; w < agg.Workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range pairs {
+					var sum, err = sdk.AddEncrypted(agg.PubKey, level[2*i], level[2*i+1])
+					if err != nil {
+						mtx.Lock()
+						firstErr = err
+						mtx.Unlock()
+						continue
+					}
+					next[i] = sum
+				}
+			}()
+		}
+
+		for i := 0; i < len(level)/2; i++ {
+			pairs <- i
+		}
+		close(pairs)
+		wg.Wait()
+		if firstErr != nil {
+			return nil, firstErr
+		}
+
+		if len(level)%2 == 1 {
+			next[len(next)-1] = level[len(level)-1]
+		}
+		level = next
+	}
+
+	if !level[0].IsEncrypted() {
+		return nil, errors.New("all the values must be encrypted")
+	}
+	return level[0], nil
+}
+
+// Function Run consumes the encrypted numbers of the provided input channel
+// and returns a channel where the Result of every window is emitted. When the
+// input channel is closed, the last incomplete tumbling window, if any, is
+// emitted and the output channel is closed. If the provided context is
+// cancelled or the aggregation fails, a Result with the error is emitted, if
+// possible, and the output channel is closed.
+func (agg *Aggregator) Run(ctx context.Context, input <-chan *number.Number) <-chan *Result {
+	var output = make(chan *Result)
+	go func() {
+		defer close(output)
+
+		var emit = func(window []*number.Number, start int) bool {
+			var result = &Result{Count: len(window), Start: start}
+			result.Sum, result.Err = agg.Sum(ctx, window)
+			select {
+			case output <- result:
+				return result.Err == nil
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var window []*number.Number
+		var start, pending = 0, 0
+		for {
+			select {
+			case <-ctx.Done():
+				select {
+				case output <- &Result{Err: ctx.Err()}:
+				default:
+				}
+				return
+			case value, ok := <-input:
+				if !ok {
+					if agg.Slide == agg.Size && len(window) > 0 {
+						emit(window, start)
+					}
+					return
+				}
+
+				window = append(window, value)
+				pending++
+				if len(window) > agg.Size {
+					window = window[1:]
+					start++
+				}
+
+				if len(window) == agg.Size && pending >= agg.Slide {
+					pending = 0
+					if !emit(window, start) {
+						return
+					}
+
+					if agg.Slide == agg.Size {
+						start += len(window)
+						window = nil
+					}
+				}
+			}
+		}
+	}()
+	return output
+}
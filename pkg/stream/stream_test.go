@@ -0,0 +1,150 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucasmenendez/gopaillier/pkg/number"
+	"github.com/lucasmenendez/gopaillier/pkg/sdk"
+)
+
+var client, _ = sdk.InitClient(128)
+
+func encrypted(t *testing.T, count int) []*number.Number {
+	var values = make([]*number.Number, count)
+	for i := range values {
+		var err error
+		if values[i], err = client.Encrypt(new(number.Number).SetInt(int64(i + 1))); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	}
+	return values
+}
+
+func feed(values []*number.Number) <-chan *number.Number {
+	var input = make(chan *number.Number)
+	go func() {
+		defer close(input)
+		for _, value := range values {
+			input <- value
+		}
+	}()
+	return input
+}
+
+func decrypt(t *testing.T, result *Result) int64 {
+	if result.Err != nil {
+		t.Fatalf("expected nil, got %s", result.Err)
+	}
+
+	var decrypted, err = client.Decrypt(result.Sum)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	return decrypted.Int()
+}
+
+func TestSum(t *testing.T) {
+	var agg, _ = NewAggregator(client.PubKey, 3, 1, 0)
+	var values = encrypted(t, 13)
+	for _, count := range []int{1, 2, 7, 13} {
+		var sum, err = agg.Sum(context.Background(), values[:count])
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if result := decrypt(t, &Result{Sum: sum}); result != int64(count*(count+1)/2) {
+			t.Fatalf("expected %d, got %d", count*(count+1)/2, result)
+		}
+	}
+
+	if _, err := agg.Sum(context.Background(), nil); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = agg.Sum(context.Background(), []*number.Number{values[0], new(number.Number).SetInt(1)}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := agg.Sum(ctx, values); err != context.Canceled {
+		t.Fatalf("expected %s, got %v", context.Canceled, err)
+	}
+}
+
+func TestTumbling(t *testing.T) {
+	var agg, _ = NewAggregator(client.PubKey, 2, 4, 0)
+	var output = agg.Run(context.Background(), feed(encrypted(t, 10)))
+
+	var expected = []struct {
+		sum          int64
+		count, start int
+	}{{10, 4, 0}, {26, 4, 4}, {19, 2, 8}}
+	var i = 0
+	for result := range output {
+		if i >= len(expected) {
+			t.Fatalf("unexpected result %+v", result)
+		} else if sum := decrypt(t, result); sum != expected[i].sum || result.Count != expected[i].count || result.Start != expected[i].start {
+			t.Fatalf("expected %+v, got %d %d %d", expected[i], sum, result.Count, result.Start)
+		}
+		i++
+	}
+	if i != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), i)
+	}
+}
+
+func TestSliding(t *testing.T) {
+	var agg, _ = NewAggregator(client.PubKey, 4, 3, 2)
+	var output = agg.Run(context.Background(), feed(encrypted(t, 8)))
+
+	// windows [1 2 3], [3 4 5], [5 6 7]
+	var expected = []int64{6, 12, 18}
+	var starts = []int{0, 2, 4}
+	var i = 0
+	for result := range output {
+		if i >= len(expected) {
+			t.Fatalf("unexpected result %+v", result)
+		} else if sum := decrypt(t, result); sum != expected[i] || result.Start != starts[i] || result.Count != 3 {
+			t.Fatalf("expected %d at %d, got %d at %d", expected[i], starts[i], sum, result.Start)
+		}
+		i++
+	}
+	if i != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), i)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	var agg, _ = NewAggregator(client.PubKey, 2, 2, 0)
+	var ctx, cancel = context.WithCancel(context.Background())
+	var input = make(chan *number.Number)
+	var output = agg.Run(ctx, input)
+
+	cancel()
+	select {
+	case <-output:
+	case <-time.After(time.Second):
+		t.Fatal("expected the output channel to be closed")
+	}
+	if _, ok := <-output; ok {
+		t.Fatal("expected the output channel to be closed")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewAggregator(client.PubKey, 0, 1, 0); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewAggregator(client.PubKey, 1, 0, 0); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewAggregator(client.PubKey, 1, 2, 3); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var agg, _ = NewAggregator(client.PubKey, 2, 2, 0)
+	var output = agg.Run(context.Background(), feed([]*number.Number{new(number.Number).SetInt(1), new(number.Number).SetInt(2)}))
+	if result := <-output; result.Err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if _, ok := <-output; ok {
+		t.Fatal("expected the output channel to be closed")
+	}
+}
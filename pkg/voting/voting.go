@@ -0,0 +1,329 @@
+// Package voting implements an homomorphic e-voting scheme over Paillier. Each
+// voter encrypts its choice into a ballot with zero-knowledge proofs of its
+// validity, the ballots are added homomorphically into an encrypted tally
+// without decrypting any of them, and only the final tally is decrypted by the
+// keyholder, who publishes the result with proofs of the correct decryption,
+// so anyone can verify it. Two ballot encodings are supported:
+//   - PerCiphertext: a ciphertext per candidate, E(1) for the chosen one and
+//     E(0) for the rest, with a proof that every ciphertext encrypts a bit and
+//     that their sum is 1.
+//   - PerSlot: a single ciphertext E(2^(i*w)) for the candidate i, where w is
+//     the number of bits of each candidate slot, with a proof that it encrypts
+//     one of the valid candidate values.
+//
+// Every ballot is bound to the identifier of its voter, which is included in
+// the transcript of its proofs, so a ballot can not be cast again under
+// another identifier and every identifier can only cast one ballot. The
+// identifiers must be authenticated by the application.
+package voting
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+var bits = []*big.Int{big.NewInt(0), big.NewInt(1)}
+var one = []*big.Int{big.NewInt(1)}
+
+// Mode defines how the choices of the ballots are encoded.
+type Mode int
+
+const (
+	// PerCiphertext encodes each candidate into its own ciphertext.
+	PerCiphertext Mode = iota
+	// PerSlot encodes all the candidates into disjoint slots of the same
+	// ciphertext.
+	PerSlot
+)
+
+// Struct Election contains the paillier.PublicKey of the keyholder, the
+// candidates, the ballot encoding mode, the encrypted tally and the number of
+// ballots cast. In PerSlot mode, SlotBits defines the size in bits of each
+// candidate slot, which limits the number of ballots to 2^SlotBits - 1. It
+// also keeps the identifiers of the voters that already cast a ballot.
+type Election struct {
+	PubKey     *paillier.PublicKey
+	Candidates []string
+	Mode       Mode
+	SlotBits   uint
+	Tally      []*big.Int
+	Votes      int
+	voters     map[string]bool
+}
+
+// Struct Ballot contains the identifier of the voter, its encrypted choices
+// and the proofs of its validity, which are bound to the voter identifier.
+type Ballot struct {
+	Voter    string
+	Choices  []*big.Int
+	Proofs   []*paillier.MembershipProof
+	SumProof *paillier.MembershipProof
+}
+
+// Struct Result contains the decrypted tally of an Election: the number of
+// votes of each candidate, the plaintexts of the encrypted tally and the
+// proofs of their correct decryption.
+type Result struct {
+	Candidates []string
+	Counts     []int64
+	Plaintexts []*big.Int
+	Proofs     []*paillier.MembershipProof
+	Votes      int
+}
+
+// Function NewElection returns a new Election with the provided
+// paillier.PublicKey, candidates and mode, initializing its encrypted tally to
+// zero. The slotBits argument is only used in PerSlot mode. It returns an
+// error if there are less than two candidates, if the mode is unknown, if the
+// slots do not fit into the plaintext space of the key or if the encryption
+// fails.
+func NewElection(pubKey *paillier.PublicKey, candidates []string, mode Mode, slotBits uint) (*Election, error) {
+	if len(candidates) < 2 {
+		return nil, errors.New("at least two candidates are required")
+	}
+
+	var election = &Election{
+		PubKey:     pubKey,
+		Candidates: candidates,
+		Mode:       mode,
+		SlotBits:   slotBits,
+		voters:     make(map[string]bool),
+	}
+	switch mode {
+	case PerCiphertext:
+		election.Tally = make([]*big.Int, len(candidates))
+	case PerSlot:
+		if slotBits < 1 {
+			return nil, errors.New("slot size must be positive")
+		} else if available := pubKey.N.BitLen() - 2; int(slotBits)*len(candidates) > available {
+			return nil, fmt.Errorf("%d slots of %d bits do not fit into the %d bits available",
+				len(candidates), slotBits, available)
+		}
+		election.Tally = make([]*big.Int, 1)
+	default:
+		return nil, errors.New("unknown ballot mode")
+	}
+
+	var err error
+	for i := range election.Tally {
+		if election.Tally[i], err = pubKey.Encrypt(big.NewInt(0)); err != nil {
+			return nil, err
+		}
+	}
+	return election, nil
+}
+
+// slotValues returns the plaintext values that encode a vote for every
+// candidate in PerSlot mode: 2^(i*w).
+func (election *Election) slotValues() []*big.Int {
+	var values = make([]*big.Int, len(election.Candidates))
+	for i := range values {
+		values[i] = new(big.Int).Lsh(big.NewInt(1), uint(i)*election.SlotBits)
+	}
+	return values
+}
+
+// Function Vote returns the encrypted Ballot of the voter provided, by its
+// identifier, for the candidate provided, by its index, including the proofs
+// of its validity bound to the voter identifier. It is intended to be used by
+// the voters. It returns an error if the voter identifier is empty, if the
+// candidate does not exist or if the encryption or the proofs generation
+// fails.
+func (election *Election) Vote(voter string, candidate int) (*Ballot, error) {
+	if voter == "" {
+		return nil, errors.New("empty voter identifier")
+	} else if candidate < 0 || candidate >= len(election.Candidates) {
+		return nil, errors.New("unknown candidate")
+	}
+
+	var key = election.PubKey
+	var label = []byte(voter)
+	var ballot = &Ballot{Voter: voter}
+	if election.Mode == PerSlot {
+		var values = election.slotValues()
+		var choice, nonce, err = key.EncryptWithNonce(values[candidate])
+		if err != nil {
+			return nil, err
+		}
+
+		var proof *paillier.MembershipProof
+		if proof, err = key.ProveLabeledMembership(choice, values[candidate], nonce, values, label); err != nil {
+			return nil, err
+		}
+
+		ballot.Choices = []*big.Int{choice}
+		ballot.Proofs = []*paillier.MembershipProof{proof}
+		return ballot, nil
+	}
+
+	// Encrypt a bit for every candidate and prove it, and prove that the
+	// product of all the choices, whose nonce is the product of their nonces,
+	// encrypts 1.
+	ballot.Choices = make([]*big.Int, len(election.Candidates))
+	ballot.Proofs = make([]*paillier.MembershipProof, len(election.Candidates))
+	var sum, sumNonce = big.NewInt(1), big.NewInt(1)
+	for i := range election.Candidates {
+		var input = bits[0]
+		if i == candidate {
+			input = bits[1]
+		}
+
+		var nonce *big.Int
+		var err error
+		if ballot.Choices[i], nonce, err = key.EncryptWithNonce(input); err != nil {
+			return nil, err
+		} else if ballot.Proofs[i], err = key.ProveLabeledMembership(ballot.Choices[i], input, nonce, bits, label); err != nil {
+			return nil, err
+		}
+
+		sum = key.AddEncrypted(sum, ballot.Choices[i])
+		sumNonce.Mod(sumNonce.Mul(sumNonce, nonce), key.N)
+	}
+
+	var err error
+	if ballot.SumProof, err = key.ProveLabeledMembership(sum, one[0], sumNonce, one, label); err != nil {
+		return nil, err
+	}
+	return ballot, nil
+}
+
+// validCiphertext returns if the provided big.Int is a valid ciphertext of the
+// key of the Election: in the range (0, n^2) and coprime with n.
+func (election *Election) validCiphertext(encrypted *big.Int) bool {
+	var key = election.PubKey
+	if encrypted == nil || encrypted.Sign() <= 0 || encrypted.Cmp(key.Nsq) >= 0 {
+		return false
+	}
+	return new(big.Int).GCD(nil, nil, encrypted, key.N).Cmp(big.NewInt(1)) == 0
+}
+
+// Function Verify returns if the Ballot provided is valid for the current
+// Election, checking that its choices are valid ciphertexts and its proofs
+// against the identifier of its voter.
+func (election *Election) Verify(ballot *Ballot) bool {
+	var key = election.PubKey
+	if ballot.Voter == "" || len(ballot.Choices) != len(election.Tally) || len(ballot.Proofs) != len(election.Tally) {
+		return false
+	}
+
+	for _, choice := range ballot.Choices {
+		if !election.validCiphertext(choice) {
+			return false
+		}
+	}
+
+	var label = []byte(ballot.Voter)
+	if election.Mode == PerSlot {
+		return key.VerifyLabeledMembership(ballot.Choices[0], election.slotValues(), label, ballot.Proofs[0])
+	}
+
+	var sum = big.NewInt(1)
+	for i, choice := range ballot.Choices {
+		if !key.VerifyLabeledMembership(choice, bits, label, ballot.Proofs[i]) {
+			return false
+		}
+		sum = key.AddEncrypted(sum, choice)
+	}
+	return key.VerifyLabeledMembership(sum, one, label, ballot.SumProof)
+}
+
+// Function Cast verifies the Ballot provided and adds its encrypted choices to
+// the encrypted tally of the Election, recording its voter identifier. It
+// returns an error if the ballot is not valid, if its voter already cast a
+// ballot or if, in PerSlot mode, the slots could overflow.
+func (election *Election) Cast(ballot *Ballot) error {
+	if !election.Verify(ballot) {
+		return errors.New("invalid ballot")
+	} else if election.voters[ballot.Voter] {
+		return fmt.Errorf("voter '%s' already cast a ballot", ballot.Voter)
+	} else if election.Mode == PerSlot && election.SlotBits < 63 && election.Votes+1 >= 1<<election.SlotBits {
+		return errors.New("maximum number of ballots reached")
+	}
+
+	for i, choice := range ballot.Choices {
+		election.Tally[i] = election.PubKey.AddEncrypted(election.Tally[i], choice)
+	}
+	if election.voters == nil {
+		election.voters = make(map[string]bool)
+	}
+	election.voters[ballot.Voter] = true
+	election.Votes++
+	return nil
+}
+
+// counts returns the number of votes of each candidate from the plaintexts of
+// the tally.
+func (election *Election) counts(plaintexts []*big.Int) []int64 {
+	var counts = make([]int64, len(election.Candidates))
+	if election.Mode == PerCiphertext {
+		for i, plaintext := range plaintexts {
+			counts[i] = plaintext.Int64()
+		}
+		return counts
+	}
+
+	var packed = new(big.Int).Set(plaintexts[0])
+	var mask = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), election.SlotBits), big.NewInt(1))
+	for i := range counts {
+		counts[i] = new(big.Int).And(packed, mask).Int64()
+		packed.Rsh(packed, election.SlotBits)
+	}
+	return counts
+}
+
+// Function Decrypt decrypts the encrypted tally of the Election with the
+// paillier.PrivateKey provided and returns the Result, including the proofs
+// of the correct decryption of every tally ciphertext. It is intended to be
+// used by the keyholder. It returns an error if the decryption fails.
+func (election *Election) Decrypt(key *paillier.PrivateKey) (*Result, error) {
+	var result = &Result{
+		Candidates: election.Candidates,
+		Plaintexts: make([]*big.Int, len(election.Tally)),
+		Proofs:     make([]*paillier.MembershipProof, len(election.Tally)),
+		Votes:      election.Votes,
+	}
+
+	var err error
+	for i, encrypted := range election.Tally {
+		if result.Plaintexts[i], result.Proofs[i], err = key.ProveDecryption(encrypted); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Counts = election.counts(result.Plaintexts)
+	return result, nil
+}
+
+// Function VerifyResult returns if the Result provided is the correct
+// decryption of the encrypted tally of the Election, checking the decryption
+// proofs, that the counts match with the plaintexts and that the total number
+// of votes matches with the number of ballots cast.
+func (election *Election) VerifyResult(result *Result) bool {
+	if len(result.Plaintexts) != len(election.Tally) || len(result.Proofs) != len(election.Tally) ||
+		len(result.Counts) != len(election.Candidates) || result.Votes != election.Votes {
+		return false
+	}
+
+	for i, encrypted := range election.Tally {
+		if result.Plaintexts[i] == nil || result.Plaintexts[i].Sign() < 0 {
+			return false
+		}
+
+		var set = []*big.Int{result.Plaintexts[i]}
+		if !election.PubKey.VerifyMembership(encrypted, set, result.Proofs[i]) {
+			return false
+		}
+	}
+
+	var total int64
+	for i, count := range election.counts(result.Plaintexts) {
+		if count != result.Counts[i] {
+			return false
+		}
+		total += count
+	}
+	return total == int64(election.Votes)
+}
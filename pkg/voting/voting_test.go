@@ -0,0 +1,209 @@
+package voting
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/lucasmenendez/gopaillier/pkg/paillier"
+)
+
+var key, _ = paillier.NewKeys(128)
+var candidates = []string{"alice", "bob", "carol"}
+
+func TestNewElection(t *testing.T) {
+	if _, err := NewElection(key.PubKey, candidates[:1], PerCiphertext, 0); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewElection(key.PubKey, candidates, Mode(5), 0); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewElection(key.PubKey, candidates, PerSlot, 0); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if _, err = NewElection(key.PubKey, candidates, PerSlot, 100); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if election, err := NewElection(key.PubKey, candidates, PerCiphertext, 0); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if len(election.Tally) != len(candidates) {
+		t.Fatalf("expected %d, got %d", len(candidates), len(election.Tally))
+	}
+
+	if election, err := NewElection(key.PubKey, candidates, PerSlot, 16); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	} else if len(election.Tally) != 1 {
+		t.Fatalf("expected 1, got %d", len(election.Tally))
+	}
+}
+
+func TestElection(t *testing.T) {
+	var votes = []int{0, 2, 1, 2, 2, 0, 2}
+	var expected = []int64{2, 1, 4}
+
+	for _, mode := range []Mode{PerCiphertext, PerSlot} {
+		var election, _ = NewElection(key.PubKey, candidates, mode, 8)
+		for i, vote := range votes {
+			var ballot, err = election.Vote(fmt.Sprintf("voter-%d", i), vote)
+			if err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			} else if err = election.Cast(ballot); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+		}
+
+		if _, err := election.Vote("voter", len(candidates)); err == nil {
+			t.Fatal("expected error, got nil")
+		} else if _, err = election.Vote("", 0); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		var result, err = election.Decrypt(key)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if result.Votes != len(votes) {
+			t.Fatalf("expected %d, got %d", len(votes), result.Votes)
+		}
+		for i, count := range result.Counts {
+			if count != expected[i] {
+				t.Fatalf("candidate %d: expected %d, got %d", i, expected[i], count)
+			}
+		}
+
+		if !election.VerifyResult(result) {
+			t.Fatal("expected valid result")
+		}
+
+		// a manipulated result is detected
+		result.Counts[0]++
+		result.Counts[1]--
+		if election.VerifyResult(result) {
+			t.Fatal("expected invalid result")
+		}
+		result.Counts[0]--
+		result.Counts[1]++
+		result.Plaintexts[0] = new(big.Int).Add(result.Plaintexts[0], big.NewInt(1))
+		if election.VerifyResult(result) {
+			t.Fatal("expected invalid result")
+		}
+	}
+}
+
+func TestInvalidBallots(t *testing.T) {
+	var election, _ = NewElection(key.PubKey, candidates, PerCiphertext, 0)
+
+	// a ballot that votes twice for the same candidate
+	var ballot, _ = election.Vote("alice", 1)
+	ballot.Choices[1] = key.PubKey.Add(ballot.Choices[1], big.NewInt(1))
+	if err := election.Cast(ballot); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// a ballot that votes for two candidates
+	var first, _ = election.Vote("bob", 0)
+	var second, _ = election.Vote("bob", 1)
+	first.Choices[1], first.Proofs[1] = second.Choices[1], second.Proofs[1]
+	if err := election.Cast(first); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// a ballot of other election mode
+	var slotElection, _ = NewElection(key.PubKey, candidates, PerSlot, 2)
+	if err := slotElection.Cast(second); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// a PerSlot ballot with a value out of the candidates
+	var slotBallot, _ = slotElection.Vote("carol", 2)
+	slotBallot.Choices[0] = key.PubKey.Mul(slotBallot.Choices[0], big.NewInt(2))
+	if err := slotElection.Cast(slotBallot); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// the slots of 2 bits allow up to 3 ballots
+	for i := 0; i < 3; i++ {
+		slotBallot, _ = slotElection.Vote(fmt.Sprintf("voter-%d", i), 0)
+		if err := slotElection.Cast(slotBallot); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	}
+	slotBallot, _ = slotElection.Vote("voter-3", 0)
+	if err := slotElection.Cast(slotBallot); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestReplayedBallots(t *testing.T) {
+	var election, _ = NewElection(key.PubKey, candidates, PerCiphertext, 0)
+	var ballot, _ = election.Vote("alice", 1)
+	if err := election.Cast(ballot); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	// the same ballot, or another ballot of the same voter, is rejected
+	var other, _ = election.Vote("alice", 2)
+	if err := election.Cast(ballot); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if err = election.Cast(other); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// the ballot is not valid under another voter identifier
+	ballot.Voter = "bob"
+	if err := election.Cast(ballot); err == nil {
+		t.Fatal("expected error, got nil")
+	} else if election.Votes != 1 {
+		t.Fatalf("expected 1, got %d", election.Votes)
+	}
+}
+
+func TestMalformedBallots(t *testing.T) {
+	for _, mode := range []Mode{PerCiphertext, PerSlot} {
+		var election, _ = NewElection(key.PubKey, candidates, mode, 8)
+		var ballot, _ = election.Vote("alice", 1)
+
+		for _, choice := range []*big.Int{nil, big.NewInt(0), key.PubKey.N, key.PubKey.Nsq} {
+			var malformed = &Ballot{
+				Voter:    ballot.Voter,
+				Choices:  append([]*big.Int{}, ballot.Choices...),
+				Proofs:   ballot.Proofs,
+				SumProof: ballot.SumProof,
+			}
+			malformed.Choices[0] = choice
+			if err := election.Cast(malformed); err == nil {
+				t.Fatalf("%v: expected error, got nil", choice)
+			}
+		}
+
+		// a ballot with zero choices and zero responses is rejected and
+		// does not modify the tally
+		var size = len(bits)
+		if mode == PerSlot {
+			size = len(candidates)
+		}
+		var zero = func(n int) *paillier.MembershipProof {
+			var proof = &paillier.MembershipProof{}
+			for i := 0; i < n; i++ {
+				proof.A = append(proof.A, big.NewInt(1))
+				proof.E = append(proof.E, big.NewInt(1))
+				proof.Z = append(proof.Z, big.NewInt(0))
+			}
+			return proof
+		}
+		var zeros = &Ballot{Voter: "mallory", SumProof: zero(1)}
+		for range election.Tally {
+			zeros.Choices = append(zeros.Choices, big.NewInt(0))
+			zeros.Proofs = append(zeros.Proofs, zero(size))
+		}
+		if err := election.Cast(zeros); err == nil {
+			t.Fatal("expected error, got nil")
+		} else if err = election.Cast(ballot); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		var result, err = election.Decrypt(key)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		} else if result.Votes != 1 || result.Counts[1] != 1 {
+			t.Fatalf("expected a single vote for candidate 1, got %v", result.Counts)
+		}
+	}
+}